@@ -507,6 +507,14 @@ func (c *Command) parseOptions(args []string) (ss []string, err error) {
 
 	Debugf("cmd: %s - will parse options from args: %v", c.Name, args)
 
+	// the negative numbers for the numeric arguments are not options
+	var rest []string
+	if c.hasNumericArg() {
+		if i := c.negativeNumAt(args); i >= 0 {
+			args, rest = args[:i], args[i:]
+		}
+	}
+
 	// parse options, don't contains command name.
 	if err = c.Parse(args); err != nil {
 		Logf(VerbCrazy, "cmd: %s - parse options, err: <red>%s</>", c.Name, err.Error())
@@ -514,7 +522,29 @@ func (c *Command) parseOptions(args []string) (ss []string, err error) {
 	}
 
 	// remaining args
-	return c.Flags.RawArgs(), nil
+	return append(c.Flags.RawArgs(), rest...), nil
+}
+
+// find the index of the first negative number token in the options part of the args. -1 is not found.
+func (c *Command) negativeNumAt(args []string) int {
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if negNumReg.MatchString(arg) {
+			return i
+		}
+
+		// end of the options
+		if arg == "--" || arg == "" || arg[0] != '-' {
+			return -1
+		}
+
+		// skip the option value. eg: "--name VAL"
+		name := strings.TrimLeft(arg, "-")
+		if !strings.Contains(name, "=") && c.Flags.needsValue(name) {
+			i++
+		}
+	}
+	return -1
 }
 
 // prepare: before execute the command
@@ -627,8 +657,10 @@ var CmdHelpTemplate = `{{.Desc}}
 {{.GOpts}}{{end}}{{if .Options}}
 <comment>Options:</>
 {{.Options}}{{end}}{{if .Cmd.Args}}
-<comment>Arguments:</>{{range $a := .Cmd.Args}}
-  <info>{{$a.HelpName | printf "%-12s"}}</>{{$a.Desc | ucFirst}}{{if $a.Required}}<red>*</>{{end}}{{end}}
+<comment>Arguments:</>{{range $a := .Cmd.Args}}{{if $a.Visible}}
+  <info>{{$.Cmd.ArgHelpName $a | printf "%-12s"}}</>{{$a.HelpDesc | ucFirst}}{{if $a.Required}}<red>*</>{{end}}{{end}}{{end}}{{if .Cmd.HelpNote}}
+  {{.Cmd.HelpNote}}{{end}}{{range $h := .Cmd.HelpHints}}
+  <mga>Hint:</> {{$h}}{{end}}
 {{end}}{{ if .Subs }}
 <comment>Sub Commands:</>{{range $n,$c := .Subs}}
   <info>{{$c.Name | paddingName }}</> {{$c.HelpDesc}}{{if $c.Aliases}} (alias: <green>{{ join $c.Aliases ","}}</>){{end}}{{end}}
@@ -325,6 +325,72 @@ func TestCommand_Run_parseHelp(t *testing.T) {
 	is.Contains(str, "arg1        Arg1 desc")
 }
 
+func TestCommand_ShowHelp_argsNote(t *testing.T) {
+	bf.Reset()
+	is := assert.New(t)
+
+	c := gcli.NewCommand("copy", "copy files", func(c *gcli.Command) {
+		c.AddArg("src", "the source path", true)
+		c.AddArg("dst", "the target path").WithDefault("./")
+		c.AddArg("debug", "the hidden argument").WithHidden()
+		c.AddReserved("mode")
+		c.SetHelpNote("Paths are relative to the project root")
+		c.SetHelpFormatter(func(a *gcli.Argument) string {
+			if a.Name == "dst" {
+				return "[dst:path]"
+			}
+			return a.HelpName()
+		})
+		c.SetHelpHintFunc(func() []string {
+			return []string{"provide SRC and DST together"}
+		})
+	})
+
+	// no color
+	color.Disable()
+	color.SetOutput(bf)
+	defer color.ResetOptions()
+
+	err := c.Run([]string{"--help"})
+	is.NoErr(err)
+	str := bf.String()
+	is.Contains(str, "src         The source path")
+	is.Contains(str, "[dst:path]  The target path (default: ./)")
+	is.NotContains(str, "the hidden argument")
+	is.Contains(str, "[reserved]  Reserved for future use")
+	is.Contains(str, "  Paths are relative to the project root")
+	is.Contains(str, "  Hint: provide SRC and DST together")
+}
+
+func TestCommand_Run_negativeNumArgs(t *testing.T) {
+	is := assert.New(t)
+
+	var str string
+	var debug bool
+	c := gcli.NewCommand("calc", "desc", func(c *gcli.Command) {
+		c.StrOpt(&str, "str", "s", "", "desc")
+		c.BoolOpt(&debug, "debug", "", false, "desc")
+		c.AddArg("num", "desc", true).WithType("int")
+		c.AddArg("ratio", "desc").WithType("float")
+	})
+	c.SetFunc(func(c *gcli.Command, args []string) error {
+		return nil
+	})
+
+	err := c.Run([]string{"--debug", "-s", "abc", "-5", "-3.14"})
+	is.NoErr(err)
+	is.True(debug)
+	is.Eq("abc", str)
+	is.Eq(-5, c.Arg("num").Val())
+	is.Eq(-3.14, c.Arg("ratio").Val())
+
+	// negative number as option value
+	err = c.Run([]string{"--str", "-1", "2"})
+	is.NoErr(err)
+	is.Eq("-1", str)
+	is.Eq(2, c.Arg("num").Val())
+}
+
 func TestCommand_Run_parseOptions(t *testing.T) {
 	bf.Reset()
 	is := assert.New(t)
@@ -415,3 +481,21 @@ func TestBooleans(t *testing.T) {
 	err = val.Set("abc")
 	is.Err(err)
 }
+
+func TestCommand_Run_argsTerminator(t *testing.T) {
+	is := assert.New(t)
+
+	var debug bool
+	c := gcli.NewCommand("run", "desc", func(c *gcli.Command) {
+		c.BoolOpt(&debug, "debug", "", false, "desc")
+		c.AddArg("files", "desc", true, true)
+	})
+	c.SetFunc(func(c *gcli.Command, args []string) error {
+		return nil
+	})
+
+	err := c.Run([]string{"--debug", "a", "b", "--", "--weird", "-x"})
+	is.NoErr(err)
+	is.True(debug)
+	is.Eq([]string{"a", "b", "--weird", "-x"}, c.Arg("files").Val())
+}
@@ -1,11 +1,39 @@
 package gcli
 
 import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"runtime"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 
+	"github.com/gookit/color"
+	"github.com/gookit/gcli/v3/interact"
+	"github.com/gookit/goutil/arrutil"
+	"github.com/gookit/goutil/envutil"
 	"github.com/gookit/goutil/errorx"
+	"github.com/gookit/goutil/fsutil"
+	"github.com/gookit/goutil/mathutil"
 	"github.com/gookit/goutil/structs"
 	"github.com/gookit/goutil/strutil"
+	"golang.org/x/text/unicode/norm"
 )
 
 /*************************************************************
@@ -39,6 +67,67 @@ type Arguments struct {
 	hasArrayArg bool
 	// mark exists optional argument
 	hasOptionalArg bool
+	// assume "yes" for all confirmation of the arguments
+	assumeYes bool
+	// tokenizer for split input line to args. default is PosixTokenizer
+	tokenizer func(line string) ([]string, error)
+	// cross-argument validators, will call them after all args bound
+	relations []func(ags *Arguments) error
+	// the validator for the whole argument set. see SetGroupValidator()
+	groupValidator func(ags *Arguments) error
+	// append new values to the exists values of the arrayed argument on parse
+	appendArray bool
+	// help note for render after the argument rows on help
+	helpNote string
+	// provide the advisory hints for render after the help note
+	helpHintFn func() []string
+	// context values for fallback on parse. see ParseArgsWithContext()
+	ctx map[string]any
+	// hook func on the parse fully successful
+	onComplete func(ags *Arguments) error
+	// std context for the async validators. see Argument.WithAsyncValidator()
+	stdCtx context.Context
+	// the sentinel token and argument name for capture the tokens after it
+	captureSentinel string
+	captureName     string
+	// allow the "name=value" tokens bind to the named argument on parse
+	allowInline bool
+	// the arguments are bound by name only. see SetBindByName()
+	bindByName bool
+	// the arrayed argument leave enough tokens for the subsequent required arguments
+	arrayLazy bool
+	// the unconsumed trailing args of the last parse
+	remaining []string
+	// the separator token between the argument groups. see SetArgSeparator()
+	argSep string
+	// the argument name that has read the stdin on parse
+	stdinBy string
+	// custom validator for the argument names. see SetNameValidator()
+	nameValidator func(name string) bool
+	// custom formatter for the argument name on help. see SetHelpFormatter()
+	helpFormatter func(a *Argument) string
+	// the output for the deprecation warnings, default is os.Stderr. see SetDeprecationOutput()
+	deprecationOut io.Writer
+	// the validate mode on parse. see SetValidateMode()
+	validateMode ValidateMode
+	// the collected errors on parse with ValidateAll mode
+	errs ArgErrors
+	// the end-of-arguments token, default is "--". see SetTerminator()
+	terminator   string
+	noTerminator bool
+	// the literal tokens after the terminator on current parse. see SetTerminator()
+	literal []string
+	// the dry-run parse, don't make side effects. see CanParse()
+	dryRun bool
+	// the struct fields for populate the bound values after parse. see BindStruct()
+	structFields []structField
+}
+
+// structField a struct field bound to an argument. see Arguments.BindStruct()
+type structField struct {
+	name string
+	fv   reflect.Value
+	arg  *Argument
 }
 
 // SetName for Arguments
@@ -51,286 +140,4027 @@ func (ags *Arguments) SetValidateNum(validateNum bool) {
 	ags.validateNum = validateNum
 }
 
-// ParseArgs for Arguments
-func (ags *Arguments) ParseArgs(args []string) (err error) {
-	var num int
-	inNum := len(args)
+// SetHelpNote set a note for render after the argument rows on help
+func (ags *Arguments) SetHelpNote(s string) {
+	ags.helpNote = s
+}
 
-	for i, arg := range ags.args {
-		// num is equals to "index + 1"
-		num = i + 1
-		if num > inNum { // not enough args
-			if arg.Required {
-				return errorx.Rawf("must set value for the argument: %s(position#%d)", arg.ShowName, arg.index)
-			}
-			break
-		}
+// HelpNote get the note of the arguments for render help
+func (ags *Arguments) HelpNote() string {
+	return ags.helpNote
+}
 
-		if arg.Arrayed {
-			err = arg.bindValue(args[i:])
-			inNum = num // must reset inNum
-		} else {
-			err = arg.bindValue(args[i])
-		}
+// SetNameValidator set a custom validator for the argument names and aliases, instead of the default
+// name rule(regGoodName). NOTE: must call it before add arguments.
+//
+// Usage:
+//
+//	// allow namespaced names. eg: db.host
+//	ags.SetNameValidator(regexp.MustCompile(`^[a-z][\w.:-]*$`).MatchString)
+func (ags *Arguments) SetNameValidator(fn func(name string) bool) {
+	ags.nameValidator = fn
+}
 
-		// has error on binding arg value
-		if err != nil {
-			return
-		}
-	}
+// SetHelpFormatter set a func to format the argument name on render help.
+// eg: render "<name:type>" or "[name]" forms.
+//
+// Usage:
+//
+//	cmd.SetHelpFormatter(func(a *gcli.Argument) string {
+//		if len(a.Choices()) > 0 {
+//			return a.Name + "{" + strings.Join(a.Choices(), "|") + "}"
+//		}
+//		return a.HelpName()
+//	})
+func (ags *Arguments) SetHelpFormatter(fn func(a *Argument) string) {
+	ags.helpFormatter = fn
+}
 
-	if ags.validateNum && inNum > num {
-		return errorx.Rawf("entered too many arguments: %v", args[num:])
+// ArgHelpName format the argument name for render help, fallback to Argument.HelpName()
+func (ags *Arguments) ArgHelpName(a *Argument) string {
+	if ags.helpFormatter != nil {
+		return ags.helpFormatter(a)
 	}
-	return
+	return a.HelpName()
 }
 
-/*************************************************************
- * command arguments
- *************************************************************/
-
-// AddArg binding a named argument for the command.
+// SetArgSeparator set a separator token for allow define multi arrayed arguments.
+// NOTE: must call it before add arguments.
 //
-// Notice:
-//   - Required argument cannot be defined after optional argument
-//   - Only one array parameter is allowed
-//   - The (array) argument of multiple values can only be defined at the end
+// each arrayed argument ends an argument group, the input args are split by the separator,
+// and each segment is bound to the group in order. the rules on define and validate are
+// applied per group, like an independent argument list:
+//   - required argument cannot be defined after optional argument in the group
+//   - the required arguments of the group must be present in its segment
+//   - the missing segments are treated as empty
 //
 // Usage:
 //
-//	cmd.AddArg("name", "description")
-//	cmd.AddArg("name", "description", true) // required
-//	cmd.AddArg("names", "description", true, true) // required and is arrayed
-func (ags *Arguments) AddArg(name, desc string, requiredAndArrayed ...bool) *Argument {
-	newArg := NewArgument(name, desc, requiredAndArrayed...)
-	return ags.AddArgument(newArg)
+//	ags.SetArgSeparator("--")
+//	ags.AddArg("files", "desc", true, true)
+//	ags.AddArg("opts", "desc", false, true)
+//	// input: a.txt b.txt -- -v -x
+func (ags *Arguments) SetArgSeparator(sep string) {
+	ags.argSep = sep
 }
 
-// AddArgByRule add an arg by simple string rule
-func (ags *Arguments) AddArgByRule(name, rule string) *Argument {
-	mp := parseSimpleRule(name, rule)
+// ValidateMode the validate mode for parse arguments
+type ValidateMode uint8
 
-	required := strutil.QuietBool(mp["required"])
-	newArg := NewArgument(name, mp["desc"], required)
+// the validate modes. see Arguments.SetValidateMode()
+const (
+	// ValidateFailFast abort the parse on the first error. it's default.
+	ValidateFailFast ValidateMode = iota
+	// ValidateAll run all validators and checks, returns all errors as ArgErrors.
+	ValidateAll
+)
 
-	if defVal := mp["default"]; defVal != "" {
-		newArg.Set(defVal)
+// SetValidateMode set the validate mode on parse, default is ValidateFailFast.
+//
+// on the ValidateAll mode, ParseArgs() binds all the arguments and runs the validators,
+// the required and count checks, then returns the errors together as ArgErrors.
+// the group validator and relations are skipped if any argument failed,
+// and the onComplete hook is only called on no error.
+//
+// NOTE: the syntax errors of the input tokens still abort the parse immediately.
+// the validators of one argument stop on the first error, unless Argument.SetCollectErrors(true).
+func (ags *Arguments) SetValidateMode(mode ValidateMode) {
+	ags.validateMode = mode
+}
+
+// handle the parse error by the validate mode. returns nil after collect the error on ValidateAll mode.
+func (ags *Arguments) fail(err error) error {
+	if err == nil || ags.validateMode != ValidateAll {
+		return err
 	}
 
-	return ags.AddArgument(newArg)
+	ags.errs = append(ags.errs, err)
+	return nil
 }
 
-// BindArg alias of the AddArgument()
-func (ags *Arguments) BindArg(arg *Argument) *Argument {
-	return ags.AddArgument(arg)
+// SetDeprecationOutput set the output for the warnings of the arguments on parse, default is os.Stderr.
+// eg: the deprecated argument is used, the coerce fallback value is used.
+func (ags *Arguments) SetDeprecationOutput(out io.Writer) {
+	ags.deprecationOut = out
 }
 
-// AddArgument binding a named argument for the command.
-//
-// Notice:
-//   - Required argument cannot be defined after optional argument
-//   - Only one array parameter is allowed
-//   - The (array) argument of multiple values can only be defined at the end
-func (ags *Arguments) AddArgument(arg *Argument) *Argument {
-	if ags.argsIndexes == nil {
-		ags.argsIndexes = make(map[string]int)
+// print a warning message on parse. see SetDeprecationOutput()
+func (ags *Arguments) warnf(format string, v ...any) {
+	out := ags.deprecationOut
+	if out == nil {
+		out = os.Stderr
 	}
+	_, _ = fmt.Fprintf(out, "WARNING: "+format+"\n", v...)
+}
 
-	// validate argument name
-	name := arg.goodArgument()
-	if _, has := ags.argsIndexes[name]; has {
-		panicf("the argument name '%s' already exists in command '%s'", name, ags.name)
+// warn the deprecated arguments bound from the input, once for each argument. see Argument.WithDeprecated()
+func (ags *Arguments) warnDeprecated() {
+	for _, arg := range ags.args {
+		if arg.deprecated == "" || arg.warned {
+			continue
+		}
+		if arg.source == ArgSourceInput || arg.source == ArgSourceStdin {
+			arg.warned = true
+			ags.warnf("the argument '%s' is deprecated, %s", arg.ShowName, arg.deprecated)
+		}
 	}
+}
 
-	if ags.hasArrayArg {
-		panicf("have defined an array argument, you cannot add argument '%s'", name)
-	}
+// SetTerminator set the end-of-arguments token for the positional binding, default is "--".
+// the tokens after the terminator are appended to the trailing arrayed argument verbatim as strings,
+// without validators.
+// if no trailing arrayed argument, they are kept as the remaining args. see Remaining()
+//
+// the empty token will disable it. the terminator is kept as a value for the raw arrayed argument,
+// and is ignored if same as the capture sentinel or the argument separator.
+//
+//	// define: <files...>
+//	// input:  a b -- --weird -x
+//	// files:  [a b --weird -x]
+func (ags *Arguments) SetTerminator(token string) {
+	ags.terminator, ags.noTerminator = token, token == ""
+}
 
-	if arg.Required && ags.hasOptionalArg {
-		panicf("required argument '%s' cannot be defined after optional argument", name)
+// split the args by the terminator token. see SetTerminator()
+func (ags *Arguments) splitTerminator(args []string) ([]string, []string) {
+	if ags.noTerminator || len(ags.args) == 0 {
+		return args, nil
 	}
 
-	// add argument index record
-	arg.index = len(ags.args)
-	ags.argsIndexes[name] = arg.index
+	term := ags.terminator
+	if term == "" {
+		term = "--"
+	}
+	if term == ags.captureSentinel || term == ags.argSep {
+		return args, nil
+	}
+	if last := ags.args[len(ags.args)-1]; last.Arrayed && last.raw {
+		return args, nil
+	}
 
-	// add argument
-	ags.args = append(ags.args, arg)
-	if !arg.Required {
-		ags.hasOptionalArg = true
+	for i, s := range args {
+		if s == term {
+			return args[:i], append([]string{}, args[i+1:]...)
+		}
 	}
+	return args, nil
+}
 
-	if arg.Arrayed {
-		ags.hasArrayArg = true
+// append the literal tokens to the trailing arrayed argument verbatim. see SetTerminator()
+func (ags *Arguments) bindLiteral(arg *Argument) {
+	if arg.countOnly {
+		n, _ := arg.V.(int)
+		arg.V = n + len(ags.literal)
+	} else {
+		arg.V = append(append([]string(nil), toStrings(arg.V)...), ags.literal...)
 	}
+	arg.source, ags.literal = ArgSourceInput, nil
+}
 
-	return arg
+// check the argument is the trailing arrayed argument and has literal tokens to bind.
+func (ags *Arguments) takesLiteral(i int) bool {
+	return len(ags.literal) > 0 && i == len(ags.args)-1 && ags.args[i].Arrayed
 }
 
-// Args get all defined argument
-func (ags *Arguments) Args() []*Argument {
-	return ags.args
+// SetHelpHintFunc set a func to provide contextual hints for render on help.
+// the hints are advisory only, will not affect the parsing.
+//
+// eg: "provide <from> and <to> together"
+func (ags *Arguments) SetHelpHintFunc(fn func() []string) {
+	ags.helpHintFn = fn
 }
 
-// HasArg check named argument is defined
-func (ags *Arguments) HasArg(name string) bool {
-	_, ok := ags.argsIndexes[name]
-	return ok
+// HelpHints get the hints of the arguments for render help
+func (ags *Arguments) HelpHints() []string {
+	if ags.helpHintFn == nil {
+		return nil
+	}
+	return ags.helpHintFn()
 }
 
-// HasArgs defined. alias of the HasArguments()
-func (ags *Arguments) HasArgs() bool {
-	return len(ags.argsIndexes) > 0
+// SetContext set the std context for run async validators. see Argument.WithAsyncValidator()
+func (ags *Arguments) SetContext(ctx context.Context) {
+	ags.stdCtx = ctx
 }
 
-// HasArguments defined
-func (ags *Arguments) HasArguments() bool {
-	return len(ags.argsIndexes) > 0
+// Context get the std context of the arguments. default is context.Background()
+func (ags *Arguments) Context() context.Context {
+	if ags.stdCtx == nil {
+		return context.Background()
+	}
+	return ags.stdCtx
 }
 
-// Arg get arg by defined name.
+// SetAssumeYes assume "yes" for all argument confirmation. useful for non-interactive use.
+func (ags *Arguments) SetAssumeYes(assumeYes bool) {
+	ags.assumeYes = assumeYes
+}
+
+// SetTokenizer set custom tokenizer for the ParseLine(), ParseReader()
+func (ags *Arguments) SetTokenizer(fn func(line string) ([]string, error)) {
+	ags.tokenizer = fn
+}
+
+// AddRelation add a cross-argument validator, will call it after all arguments bound.
+//
+// multiple relations run in registration order, returns the first failure.
 //
 // Usage:
 //
-//	intVal := ags.Arg("name").Int()
-//	strVal := ags.Arg("name").String()
-//	arrVal := ags.Arg("names").Array()
-func (ags *Arguments) Arg(name string) *Argument {
-	i, ok := ags.argsIndexes[name]
-	if !ok {
-		panicf("get not exists argument '%s'", name)
-	}
-	return ags.args[i]
+//	cmd.AddRelation(func(ags *gcli.Arguments) error {
+//		if ags.Arg("from").String() == ags.Arg("to").String() {
+//			return errors.New("the <to> cannot be same as <from>")
+//		}
+//		return nil
+//	})
+func (ags *Arguments) AddRelation(fn func(ags *Arguments) error) {
+	ags.relations = append(ags.relations, fn)
 }
 
-// ArgByIndex get named arg by index
-func (ags *Arguments) ArgByIndex(i int) *Argument {
-	if i >= len(ags.args) {
-		panicf("get not exists argument #%d", i)
-	}
-	return ags.args[i]
+// SetGroupValidator set a validator for the whole argument set, it is called once on parse
+// after all arguments bound and count-checked, before the relations. see AddRelation()
+//
+// the returned error is returned by ParseArgs() unchanged.
+func (ags *Arguments) SetGroupValidator(fn func(ags *Arguments) error) {
+	ags.groupValidator = fn
 }
 
-/*************************************************************
- * Argument definition
- *************************************************************/
+// ParseArgsMap parse the name-keyed input values, with positional fallback.
+//
+// first binds named values to the matching arguments, the arrayed value is split by ",".
+// then binds the positional values by position: positional[i] binds to the argument at
+// position i, the arrayed argument consumes the remaining values. the fallback, required and
+// post-parse rules are applied as ParseArgs().
+//
+// returns error on:
+//   - unknown argument name
+//   - a named argument is also covered positionally
+//   - required argument is not bound
+func (ags *Arguments) ParseArgsMap(named map[string]string, positional []string) (err error) {
+	ags.resetRun()
 
-// Argument a command argument definition
-type Argument struct {
-	*structs.Value
-	// Name argument name. it's required
-	Name string
-	// Desc argument description message
-	Desc string
-	// Type name. eg: string, int, array
-	// Type string
+	names := make([]string, 0, len(named))
+	for name := range named {
+		names = append(names, name)
+	}
+	sort.Strings(names)
 
-	// ShowName is a name for display help. default is equals to Name.
-	ShowName string
-	// Required arg is required
-	Required bool
-	// Arrayed if is array, can allow to accept multi values, and must in last.
-	Arrayed bool
+	bound := make(map[int]bool, len(named))
+	for _, name := range names {
+		idx, ok := ags.lookupIndex(name)
+		if !ok {
+			return errorx.Rawf("unknown argument name '%s'", name)
+		}
 
-	// Handler custom argument value handler on call GetValue()
-	Handler func(val any) any
-	// Validator you can add a validator, will call it on binding argument value
-	Validator func(val any) (any, error)
-	// the argument position index in all arguments(cmd.args[index])
-	index int
-}
+		arg, val := ags.args[idx], named[name]
+		if arg.Arrayed {
+			err = ags.bindInput(arg, strutil.Split(val, ","))
+		} else {
+			err = ags.bindInput(arg, val)
+		}
 
-// NewArg quick create a new command argument
-func NewArg(name, desc string, val any, requiredAndArrayed ...bool) *Argument {
-	var arrayed, required bool
-	if ln := len(requiredAndArrayed); ln > 0 {
-		required = requiredAndArrayed[0]
-		if ln > 1 {
-			arrayed = requiredAndArrayed[1]
+		if err = ags.fail(arg.invalidErr(err)); err != nil {
+			return err
 		}
+		bound[idx] = true
 	}
 
-	return &Argument{
-		Name:  name,
-		Desc:  desc,
-		Value: structs.NewValue(val),
-		// other settings
-		// ShowName: name,
-		Required: required,
-		Arrayed:  arrayed,
+	var pos int
+	for i, arg := range ags.args {
+		if pos >= len(positional) {
+			if bound[i] {
+				continue
+			}
+
+			ok, fErr := ags.bindFallback(arg)
+			if fErr != nil {
+				err = arg.invalidErr(fErr)
+			} else if !ok && arg.Required {
+				err = arg.missingErr()
+			}
+		} else if bound[i] {
+			err = errorx.Rawf("the argument '%s' is set by name, but also covered positionally", arg.ShowName)
+			pos++
+		} else if arg.Arrayed {
+			err = arg.invalidErr(ags.bindInput(arg, positional[pos:]))
+			pos = len(positional)
+		} else {
+			err = arg.invalidErr(ags.bindInput(arg, positional[pos]))
+			pos++
+		}
+
+		if err = ags.fail(err); err != nil {
+			return err
+		}
+	}
+
+	if pos < len(positional) {
+		ags.remaining = append([]string(nil), positional[pos:]...)
+		if ags.validateNum {
+			if err = ags.fail(tooManyErr(pos, positional[pos:])); err != nil {
+				return err
+			}
+		}
 	}
+	return ags.afterBind()
 }
 
-// NewArgument quick create a new command argument
-func NewArgument(name, desc string, requiredAndArrayed ...bool) *Argument {
-	return NewArg(name, desc, nil, requiredAndArrayed...)
+// SetCaptureAfter all tokens after the sentinel token will bind to the named arrayed argument verbatim,
+// the earlier tokens bind positionally. if the sentinel is not present, will parse as normal.
+//
+// NOTE: the first "--" in the command line is consumed by the options parser as end-of-options,
+// so if use "--" as the sentinel, it must appear after the first "--". recommend use another sentinel.
+//
+// Usage:
+//
+//	// cmd <name> ::: <msg...>
+//	cmd.SetCaptureAfter(":::", "msg")
+func (ags *Arguments) SetCaptureAfter(sentinel, argName string) {
+	ags.captureSentinel = sentinel
+	ags.captureName = argName
 }
 
-// SetArrayed the argument
-func (a *Argument) SetArrayed() *Argument {
-	a.Arrayed = true
-	return a
+// get the capture argument. see SetCaptureAfter()
+func (ags *Arguments) captureArg() (*Argument, error) {
+	idx, ok := ags.lookupIndex(ags.captureName)
+	if !ok {
+		return nil, errorx.Rawf("the capture argument '%s' is not exists", ags.captureName)
+	}
+
+	arg := ags.args[idx]
+	if !arg.Arrayed {
+		return nil, errorx.Rawf("the capture argument '%s' must be arrayed", ags.captureName)
+	}
+	return arg, nil
 }
 
-// WithValue to the argument
-func (a *Argument) WithValue(val any) *Argument {
-	a.Value.Set(val)
-	return a
+// SetAllowInlineNames allow the "name=value" tokens bind value to the named argument
+// regardless of position, the bare tokens bind positionally to the remaining arguments.
+//
+// will return error on the name is unknown. eg:
+//
+//	// define: <src> <dst>
+//	// input:  dst=./b ./a
+func (ags *Arguments) SetAllowInlineNames(allow bool) {
+	ags.allowInline = allow
 }
 
-// WithFn a func for config the argument
-func (a *Argument) WithFn(fn func(arg *Argument)) *Argument {
-	if fn != nil {
-		fn(a)
+// SetBindByName set the arguments are bound by name only, the input tokens must be "name=value".
+// the repeated tokens of an arrayed argument are collected.
+//
+// since the position is irrelevant, the ordering rules on add argument are not applied.
+// eg: required argument can be defined after optional argument.
+// NOTE: must call it before add arguments.
+//
+//	// define: [verbose] <target>
+//	// input:  target=prod verbose=1
+func (ags *Arguments) SetBindByName(byName bool) {
+	ags.bindByName = byName
+}
+
+// SetArrayGreedy set the arrayed argument binding mode. default is greedy.
+//
+//   - greedy: the arrayed argument consumes all remaining tokens.
+//   - lazy: the arrayed argument leaves enough tokens to satisfy the subsequent required arguments.
+//     allocation: the arrayed argument at cursor P takes tokens [P, N-R), R is the number of
+//     the required arguments after it, N is the number of tokens. if N-R <= P, it takes nothing.
+//
+// NOTE: currently the arrayed argument must be the last one, so the lazy mode is the groundwork
+// for support layouts like "files... <dest>".
+func (ags *Arguments) SetArrayGreedy(greedy bool) {
+	ags.arrayLazy = !greedy
+}
+
+// count the required arguments after the index
+func (ags *Arguments) requiredAfter(i int) (n int) {
+	for _, arg := range ags.args[i+1:] {
+		if arg.Required {
+			n++
+		}
 	}
-	return a
+	return
 }
 
-// WithValidator set a value validator of the argument
-func (a *Argument) WithValidator(fn func(any) (any, error)) *Argument {
-	a.Validator = fn
-	return a
+// SetOnComplete set a hook func, will call it at the end of ParseArgs() on success.
+// returns error will fail the parse.
+func (ags *Arguments) SetOnComplete(fn func(ags *Arguments) error) {
+	ags.onComplete = fn
 }
 
-// SetValue set an validated value
-func (a *Argument) SetValue(val any) error {
-	return a.bindValue(val)
+// ParseArgsAppend like ParseArgs(), but the values of the arrayed argument
+// will be appended to the exists values, rather than replaced.
+//
+// NOTE: only the new values are passed to the validator and handler. the WithUnique(), WithSorted(),
+// WithCountOnly() and the value count checks(eg: WithCountEqualTo) are applied to the accumulated values. the fallback
+// values(eg: WithDefault) only bind to the argument that has no value yet.
+func (ags *Arguments) ParseArgsAppend(args []string) error {
+	ags.appendArray = true
+	defer func() { ags.appendArray = false }()
+
+	return ags.ParseArgs(args)
 }
 
-// Init the argument
-func (a *Argument) Init() *Argument {
-	a.goodArgument()
-	return a
+// ParseArgsWithContext like ParseArgs(), the absent arguments will fall back to the
+// context value. see Argument.WithContextDefault()
+//
+// value precedence: positional > context > static default
+func (ags *Arguments) ParseArgsWithContext(ctx map[string]any, args []string) error {
+	ags.ctx = ctx
+	defer func() { ags.ctx = nil }()
+
+	return ags.ParseArgs(args)
 }
 
-func (a *Argument) goodArgument() string {
-	name := strings.TrimSpace(a.Name)
-	if name == "" {
-		panicf("the command argument name cannot be empty")
+// bind fallback value for the argument that is absent in the input args.
+//
+// fallback precedence: context > env > default
+func (ags *Arguments) bindFallback(arg *Argument) (bool, error) {
+	// skip on the unsupported OS, or keep the accumulated values on append
+	if !arg.supportedOS() || ags.appendArray && arg.HasValue() {
+		return true, nil
 	}
 
-	if !goodName.MatchString(name) {
-		panicf("the argument name '%s' is invalid, must match: %s", name, regGoodName)
+	if arg.ctxKey != "" && ags.ctx != nil {
+		if val, ok := ags.ctx[arg.ctxKey]; ok {
+			return true, arg.invalidErr(arg.bindFrom(val, ArgSourceContext))
+		}
 	}
 
-	a.Name = name
-	if a.ShowName == "" {
-		a.ShowName = name
+	if arg.envName != "" {
+		if val, ok := os.LookupEnv(arg.envName); ok {
+			if arg.Arrayed {
+				return true, arg.invalidErr(arg.bindFrom(strutil.Split(val, arg.envSep), ArgSourceEnv))
+			}
+			return true, arg.invalidErr(arg.bindFrom(val, ArgSourceEnv))
+		}
 	}
 
-	if a.Value == nil {
-		a.Value = structs.NewValue(nil)
+	if arg.hasDefault {
+		return true, arg.invalidErr(arg.bindFrom(arg.defVal, ArgSourceDefault))
 	}
-	return name
+	return false, nil
 }
 
-// GetValue get value by custom handler func
-func (a *Argument) GetValue() interface{} {
-	val := a.Value.Val()
-	if a.Handler != nil {
-		return a.Handler(val)
-	}
-	return val
+// Framing the tokens framing of the bytes data. see Arguments.ParseArgsBytes()
+type Framing uint8
+
+// the framings of the bytes data
+const (
+	// FramingNewline tokens are separated by "\n". a trailing "\r" of each token is removed,
+	// a trailing "\n" of the data does not produce an empty token.
+	FramingNewline Framing = iota
+	// FramingNUL tokens are separated by NUL byte(0x00), like "xargs -0" and "find -print0".
+	// a trailing NUL of the data does not produce an empty token.
+	FramingNUL
+	// FramingLengthPrefix each token is prefixed by its length as 4 bytes big-endian uint32.
+	FramingLengthPrefix
+)
+
+// ParseArgsBytes split the bytes data to tokens by framing, then parse by ParseArgs()
+//
+// Usage:
+//
+//	// find . -print0 | mytool
+//	data, _ := io.ReadAll(os.Stdin)
+//	err := cmd.ParseArgsBytes(data, gcli.FramingNUL)
+func (ags *Arguments) ParseArgsBytes(data []byte, framing Framing) error {
+	args, err := splitFrames(data, framing)
+	if err != nil {
+		return err
+	}
+	return ags.ParseArgs(args)
+}
+
+// split the bytes data to tokens by framing
+func splitFrames(data []byte, framing Framing) ([]string, error) {
+	var args []string
+	switch framing {
+	case FramingNewline, FramingNUL:
+		if len(data) == 0 {
+			return args, nil
+		}
+
+		sep := byte('\n')
+		if framing == FramingNUL {
+			sep = 0
+		}
+
+		// remove the trailing separator
+		if data[len(data)-1] == sep {
+			data = data[:len(data)-1]
+		}
+
+		for _, bs := range bytes.Split(data, []byte{sep}) {
+			if framing == FramingNewline {
+				bs = bytes.TrimSuffix(bs, []byte{'\r'})
+			}
+			args = append(args, string(bs))
+		}
+	case FramingLengthPrefix:
+		for len(data) > 0 {
+			if len(data) < 4 {
+				return nil, errorx.Raw("invalid length-prefixed data: incomplete length prefix")
+			}
+
+			size := binary.BigEndian.Uint32(data)
+			data = data[4:]
+			if uint64(len(data)) < uint64(size) {
+				return nil, errorx.Rawf("invalid length-prefixed data: want %d bytes, but only %d", size, len(data))
+			}
+
+			args = append(args, string(data[:size]))
+			data = data[size:]
+		}
+	default:
+		return nil, errorx.Rawf("unknown tokens framing: %d", framing)
+	}
+	return args, nil
+}
+
+// ParseLine split the input line by tokenizer, then parse to Arguments
+func (ags *Arguments) ParseLine(line string) error {
+	fn := ags.tokenizer
+	if fn == nil {
+		fn = PosixTokenizer
+	}
+
+	args, err := fn(line)
+	if err != nil {
+		return err
+	}
+	return ags.ParseArgs(args)
+}
+
+// ParseReader read all contents from reader, then parse by ParseLine()
+func (ags *Arguments) ParseReader(r io.Reader) error {
+	bs, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	return ags.ParseLine(string(bs))
+}
+
+// ParseArgs for Arguments
+func (ags *Arguments) ParseArgs(args []string) (err error) {
+	ags.resetRun()
+	args, ags.literal = ags.splitTerminator(args)
+	if ags.allowInline || ags.bindByName {
+		return ags.parseInlineNamed(args)
+	}
+
+	if ags.argSep != "" {
+		err = ags.parseSegments(args)
+	} else {
+		err = ags.bindPositional(args)
+	}
+	ags.literal = nil
+
+	if err != nil {
+		return err
+	}
+	return ags.afterBind()
+}
+
+// split the input args by the separator, and bind each segment to the argument group.
+// see SetArgSeparator()
+func (ags *Arguments) parseSegments(args []string) error {
+	groups := ags.argGroups()
+	// the tokens after the raw argument group start are not split
+	rawAt := len(groups)
+	for i, group := range groups {
+		if group[len(group)-1].raw {
+			rawAt = i
+			break
+		}
+	}
+
+	var segments [][]string
+	var starts []int
+	start := 0
+	for i, s := range args {
+		if s == ags.argSep && len(segments) < rawAt {
+			segments = append(segments, args[start:i])
+			starts = append(starts, start)
+			start = i + 1
+		}
+	}
+	starts = append(starts, start)
+	segments = append(segments, args[start:])
+
+	for i, group := range groups {
+		var seg []string
+		if i < len(segments) {
+			seg = segments[i]
+		}
+
+		// bind the segment like an independent argument list
+		nags := *ags
+		nags.args, nags.captureSentinel = group, ""
+		// the literal tokens are for the last group
+		if i < len(groups)-1 {
+			nags.literal = nil
+		}
+		err := nags.bindPositional(seg)
+		ags.stdinBy, ags.errs = nags.stdinBy, nags.errs
+		if err != nil {
+			return err
+		}
+		ags.remaining = append(ags.remaining, nags.remaining...)
+	}
+
+	if len(segments) > len(groups) {
+		for _, seg := range segments[len(groups):] {
+			ags.remaining = append(ags.remaining, seg...)
+		}
+		if ags.validateNum {
+			return ags.fail(&ArgParseError{
+				Kind:     ArgErrTooMany,
+				Position: starts[len(groups)],
+				Err:      errorx.Rawf("entered too many argument groups separated by '%s'", ags.argSep),
+			})
+		}
+	}
+	return nil
+}
+
+// split the arguments to groups, each arrayed argument ends a group.
+func (ags *Arguments) argGroups() [][]*Argument {
+	var groups [][]*Argument
+	start := 0
+	for i, arg := range ags.args {
+		if arg.Arrayed {
+			groups = append(groups, ags.args[start:i+1])
+			start = i + 1
+		}
+	}
+
+	if start < len(ags.args) || len(groups) == 0 {
+		groups = append(groups, ags.args[start:])
+	}
+	return groups
+}
+
+// bind the input args to the arguments by position.
+func (ags *Arguments) bindPositional(args []string) (err error) {
+	var captureArg *Argument
+	var captured []string
+	if ags.captureSentinel != "" {
+		if captureArg, err = ags.captureArg(); err != nil {
+			return err
+		}
+
+		for i, s := range args {
+			if s == ags.captureSentinel {
+				captured = args[i+1:]
+				args = args[:i]
+				break
+			}
+		}
+
+		// not found the sentinel, bind positionally as normal
+		if captured == nil {
+			captureArg = nil
+		}
+	}
+
+	// pos is the cursor of the input args
+	var pos int
+	inNum := len(args)
+	for i, arg := range ags.args {
+		if arg == captureArg {
+			if len(captured) == 0 && arg.Required {
+				if err = ags.fail(arg.missingErr()); err != nil {
+					return err
+				}
+				continue
+			}
+
+			// bind captured values verbatim
+			arg.V, arg.source = captured, ArgSourceInput
+			continue
+		}
+
+		// the end position of the values for the arrayed argument
+		end := inNum
+		if arg.Arrayed && ags.arrayLazy {
+			if end -= ags.requiredAfter(i); end < pos {
+				end = pos
+			}
+		}
+
+		if pos >= end { // not enough args
+			if ags.takesLiteral(i) {
+				if !ags.appendArray {
+					arg.V = nil
+				}
+				ags.bindLiteral(arg)
+				continue
+			}
+
+			ok, err := ags.bindFallback(arg)
+			if err != nil {
+				if err = ags.fail(err); err != nil {
+					return err
+				}
+				continue
+			}
+
+			if !ok && arg.Required {
+				if err = ags.fail(arg.missingErr()); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		if arg.Arrayed {
+			err = ags.bindInput(arg, args[pos:end])
+			if err == nil && ags.takesLiteral(i) {
+				ags.bindLiteral(arg)
+			}
+			pos = end
+		} else {
+			err = ags.bindInput(arg, args[pos])
+			pos++
+		}
+
+		// has error on binding arg value
+		if err = ags.fail(arg.invalidErr(err)); err != nil {
+			return err
+		}
+	}
+
+	if inNum > pos {
+		ags.remaining = append([]string(nil), args[pos:]...)
+		if ags.validateNum {
+			if err = ags.fail(tooManyErr(pos, args[pos:])); err != nil {
+				return err
+			}
+		}
+	}
+
+	// no trailing arrayed argument for the literal tokens
+	ags.remaining = append(ags.remaining, ags.literal...)
+	ags.literal = nil
+	return nil
+}
+
+// BindArgs bind the typed values to the arguments by position, without convert to strings.
+// the arrayed argument consumes the remaining values as []any. the required, fallback, count
+// and post-parse rules are applied as ParseArgs(), the errors are collected on ValidateAll mode.
+//
+// NOTE: the validators and handlers receive the typed values.
+//
+// Usage:
+//
+//	err := ags.BindArgs([]any{"deploy", 3, true})
+func (ags *Arguments) BindArgs(values []any) error {
+	ags.resetRun()
+
+	var pos int
+	for _, arg := range ags.args {
+		if pos >= len(values) {
+			ok, err := ags.bindFallback(arg)
+			if err == nil && !ok && arg.Required {
+				err = arg.missingErr()
+			}
+			if err = ags.fail(err); err != nil {
+				return err
+			}
+			continue
+		}
+
+		var err error
+		if arg.Arrayed {
+			err = ags.bindInput(arg, append([]any(nil), values[pos:]...))
+			pos = len(values)
+		} else {
+			err = ags.bindInput(arg, values[pos])
+			pos++
+		}
+
+		if err = ags.fail(arg.invalidErr(err)); err != nil {
+			return err
+		}
+	}
+
+	if ags.validateNum && pos < len(values) {
+		err := &ArgParseError{
+			Kind:     ArgErrTooMany,
+			Position: pos,
+			Err:      errorx.Rawf("entered too many arguments: %v", values[pos:]),
+		}
+		if err := ags.fail(err); err != nil {
+			return err
+		}
+	}
+	return ags.afterBind()
+}
+
+// MustParseArgs like ParseArgs(), but will panic on error.
+func (ags *Arguments) MustParseArgs(args []string) {
+	if err := ags.ParseArgs(args); err != nil {
+		panicf("parse arguments error: %s", err.Error())
+	}
+}
+
+// ParseArgsRemain like ParseArgs(), and returns the unconsumed trailing args.
+// the trailing arrayed argument will consume all remaining args, so remain is empty.
+//
+// Usage:
+//
+//	remain, err := cmd.ParseArgsRemain(args)
+//	// forward to subprocess
+//	exec.Command("tool", remain...)
+func (ags *Arguments) ParseArgsRemain(args []string) (remain []string, err error) {
+	err = ags.ParseArgs(args)
+	return ags.remaining, err
+}
+
+// Remaining get the unconsumed trailing args of the last parse
+func (ags *Arguments) Remaining() []string {
+	return ags.remaining
+}
+
+// Reset clear the bound values of all arguments and the remaining args, the definitions are kept.
+// the default values(see Argument.WithDefault()) will be bound on next parse.
+//
+// Usage:
+//
+//	for req := range requests {
+//		ags.Reset()
+//		err := ags.ParseArgs(req.Args)
+//	}
+func (ags *Arguments) Reset() {
+	ags.resetRun()
+	for _, arg := range ags.args {
+		arg.V, arg.source = nil, ArgSourceNone
+	}
+}
+
+// reset the state of the last run: the remaining and literal args, the stdin binding and the collected errors.
+func (ags *Arguments) resetRun() {
+	ags.remaining, ags.literal, ags.stdinBy, ags.errs = nil, nil, "", nil
+}
+
+// Values get a snapshot of the bound values, keyed by argument name.
+// the argument without value and the positional-only argument are excluded.
+func (ags *Arguments) Values() map[string]any {
+	mp := make(map[string]any, len(ags.args))
+	for _, arg := range ags.args {
+		if arg.HasValue() && !arg.positionalOnly {
+			mp[arg.Name] = deepCopyValue(arg.V)
+		}
+	}
+	return mp
+}
+
+// SetValues bind a set of values by argument name, each value will be validated.
+// can be used to replay a snapshot from Values(), so the values are taken as decoded:
+// the file expanding, CSV splitting and normalizers(eg: WithDecode()) are skipped.
+//
+// returns error if a name is not defined, or a required argument has no value.
+// the value of an arrayed argument can be a single value or a slice.
+func (ags *Arguments) SetValues(values map[string]any) error {
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		idx, ok := ags.lookupIndex(name)
+		if !ok {
+			return errorx.Rawf("unknown argument name '%s'", name)
+		}
+
+		arg, val := ags.args[idx], values[name]
+		rv := reflect.ValueOf(val)
+		isSlice := val != nil && rv.Kind() == reflect.Slice
+		if arg.Arrayed {
+			if _, ok := val.([]string); !ok {
+				val = toStrings(val)
+			}
+		} else if isSlice {
+			return errorx.Rawf("argument '%s' is not arrayed, cannot set multi values", arg.ShowName)
+		}
+
+		if err := arg.restoreValue(val); err != nil {
+			return err
+		}
+	}
+
+	for _, arg := range ags.args {
+		if arg.Required && !arg.HasValue() {
+			return arg.missingErr()
+		}
+	}
+	return nil
+}
+
+// CanParse check the input args can be parsed successfully, without change the argument values.
+//
+// it is a dry-run of ParseArgs() on a clone of the arguments. the confirmation is assumed "yes",
+// and will not re-prompt on bind failure, the OnComplete hook and value loggers are not called.
+// the deprecation warnings are not printed, the stdin is not read and the bound struct is not changed.
+//
+// Usage:
+//
+//	for _, ags := range candidates {
+//		if ags.CanParse(args) {
+//			return ags.ParseArgs(args)
+//		}
+//	}
+func (ags *Arguments) CanParse(args []string) bool {
+	return ags.dryRunClone().ParseArgs(args) == nil
+}
+
+// clone the arguments for dry-run parse, the side effects are disabled.
+func (ags *Arguments) dryRunClone() *Arguments {
+	nags := ags.CloneWithValues()
+	nags.dryRun, nags.assumeYes = true, true
+	nags.onComplete = nil
+	nags.structFields = nil
+	nags.deprecationOut = io.Discard
+	for _, arg := range nags.args {
+		arg.retryTimes = 0
+		arg.valueLogger = nil
+		arg.confirmMsg = ""
+	}
+	return nags
+}
+
+// read the whole stdin and bind to the argument. see Argument.WithStdin()
+func (ags *Arguments) bindStdin(arg *Argument) error {
+	if ags.stdinBy != "" {
+		return errorx.Rawf("argument '%s' cannot read from stdin, it has been read by argument '%s'", arg.ShowName, ags.stdinBy)
+	}
+	if stdinIsTerminal() {
+		return errorx.Rawf("argument '%s' reads from stdin, but nothing is piped", arg.ShowName)
+	}
+
+	ags.stdinBy = arg.ShowName
+	// don't consume the stdin on dry-run
+	if ags.dryRun {
+		arg.source = ArgSourceStdin
+		return nil
+	}
+
+	bs, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		return err
+	}
+
+	str := strings.TrimSuffix(strings.ReplaceAll(string(bs), "\r\n", "\n"), "\n")
+	if arg.Arrayed {
+		var lines []string
+		if str != "" {
+			lines = strings.Split(str, "\n")
+		}
+		return arg.bindFrom(lines, ArgSourceStdin)
+	}
+	return arg.bindFrom(str, ArgSourceStdin)
+}
+
+// run the post-parse checks and hooks after all arguments bound.
+func (ags *Arguments) afterBind() error {
+	ags.warnDeprecated()
+
+	for _, arg := range ags.args {
+		if arg.requiredIf != nil && !arg.HasValue() && arg.requiredIf(ags) {
+			if err := ags.fail(arg.missingErr()); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := ags.checkArgCount(); err != nil {
+		return err
+	}
+	if err := ags.checkCountEqual(); err != nil {
+		return err
+	}
+
+	// the group validator and relations run only on all arguments bound successfully
+	if len(ags.errs) > 0 {
+		return ags.errs
+	}
+
+	if ags.groupValidator != nil {
+		if err := ags.groupValidator(ags); err != nil {
+			return err
+		}
+	}
+
+	for _, fn := range ags.relations {
+		if err := ags.fail(fn(ags)); err != nil {
+			return err
+		}
+	}
+
+	if len(ags.errs) > 0 {
+		return ags.errs
+	}
+
+	// confirm the values from any source. see Argument.RequireConfirm()
+	for _, arg := range ags.args {
+		if err := ags.confirmArg(arg); err != nil {
+			return err
+		}
+	}
+
+	for _, sf := range ags.structFields {
+		if err := fillField(sf.fv, sf.name, sf.arg); err != nil {
+			return err
+		}
+	}
+
+	if ags.onComplete != nil {
+		return ags.onComplete(ags)
+	}
+	return nil
+}
+
+// ParseNamedArgs parse the "name=value" tokens in any order, bind them to the named arguments.
+// the bare tokens bind positionally to the arguments not yet set, in definition order.
+// the required, validator and default rules are applied as ParseArgs().
+//
+// it's same as ParseArgs() with SetAllowInlineNames(true).
+//
+//	// define: <src> <dst> [mode]
+//	// input:  mode=fast dst=./b ./a
+func (ags *Arguments) ParseNamedArgs(args []string) error {
+	ags.resetRun()
+	args, ags.literal = ags.splitTerminator(args)
+	return ags.parseInlineNamed(args)
+}
+
+// parse args with inline names. see SetAllowInlineNames()
+func (ags *Arguments) parseInlineNamed(args []string) error {
+	named := make(map[int][]string)
+	var positional []string
+	for _, tok := range args {
+		name, val, ok := strings.Cut(tok, "=")
+		if !ok || name == "" {
+			if ags.bindByName {
+				return errorx.Rawf("the token %q must be in the form 'name=value' on bind by name", tok)
+			}
+			positional = append(positional, tok)
+			continue
+		}
+
+		idx, has := ags.lookupIndex(name)
+		if !has {
+			return errorx.Rawf("unknown argument name '%s' in the token %q, valid names: %s", name, tok, strings.Join(ags.names(), ", "))
+		}
+		named[idx] = append(named[idx], val)
+	}
+
+	var pos int
+	for i, arg := range ags.args {
+		var err error
+		if vals, ok := named[i]; ok {
+			if arg.Arrayed {
+				err = ags.bindInput(arg, vals)
+			} else {
+				err = ags.bindInput(arg, vals[len(vals)-1])
+			}
+		} else if pos < len(positional) {
+			if arg.Arrayed {
+				err = ags.bindInput(arg, positional[pos:])
+				pos = len(positional)
+			} else {
+				err = ags.bindInput(arg, positional[pos])
+				pos++
+			}
+		} else if ags.takesLiteral(i) {
+			arg.V = nil
+		} else {
+			ok, fErr := ags.bindFallback(arg)
+			if fErr != nil {
+				err = fErr
+			} else if !ok && arg.Required {
+				err = arg.missingErr()
+			}
+		}
+
+		if err == nil && ags.takesLiteral(i) {
+			ags.bindLiteral(arg)
+		}
+		if err = ags.fail(arg.invalidErr(err)); err != nil {
+			return err
+		}
+	}
+
+	if ags.validateNum && pos < len(positional) {
+		if err := ags.fail(tooManyErr(pos, positional[pos:])); err != nil {
+			return err
+		}
+	}
+
+	// no trailing arrayed argument for the literal tokens
+	ags.remaining = append(ags.remaining, ags.literal...)
+	ags.literal = nil
+	return ags.afterBind()
+}
+
+// CompleteArgs returns completion candidates for the positional argument
+// the cursor is on.
+//
+// args are the already entered tokens, current is the partial token at cursor.
+// the candidates are provided by the argument completer(or choices) and filtered by the current prefix.
+//
+// Usage:
+//
+//	// input: "cmd arg0 ar"
+//	list := ags.CompleteArgs([]string{"arg0"}, "ar")
+func (ags *Arguments) CompleteArgs(args []string, current string) []string {
+	pos := len(args)
+	argNum := len(ags.args)
+	if argNum == 0 {
+		return nil
+	}
+
+	var arg *Argument
+	if pos < argNum {
+		arg = ags.args[pos]
+	} else if last := ags.args[argNum-1]; last.Arrayed {
+		arg = last
+	} else {
+		return nil
+	}
+
+	return arg.Complete(current)
+}
+
+// ExplainParse dry-run parse the args on a clone and returns a step-by-step human explanation.
+// it will not change the argument values. secret values are masked by "****".
+//
+// eg:
+//
+//	'foo' → <src> (required, input, ok)
+//	'bar','baz' → [files...] (optional, 2 values, input, ok)
+//	'info' → [level] (optional, default, ok)
+func (ags *Arguments) ExplainParse(args []string) string {
+	nags := ags.dryRunClone()
+	nags.validateMode = ValidateAll
+
+	var others []error
+	argErrs := make(map[string]error)
+	if err := nags.ParseArgs(args); err != nil {
+		errs, ok := err.(ArgErrors)
+		if !ok {
+			errs = ArgErrors{err}
+		}
+
+		for _, e := range errs {
+			pe, ok := e.(*ArgParseError)
+			if !ok || pe.ArgName == "" {
+				others = append(others, e)
+			} else if _, has := argErrs[pe.ArgName]; !has {
+				argErrs[pe.ArgName] = e
+			}
+		}
+	}
+
+	var sb strings.Builder
+	for _, arg := range nags.args {
+		kind := "optional"
+		if arg.Required {
+			kind = "required"
+		}
+
+		if err, ok := argErrs[arg.Name]; ok {
+			sb.WriteString(fmt.Sprintf("%s (%s, error: %s)\n", arg.signName(), kind, err.Error()))
+			continue
+		}
+
+		if arg.source == ArgSourceNone {
+			sb.WriteString(fmt.Sprintf("%s (%s, not set)\n", arg.signName(), kind))
+			continue
+		}
+
+		// the stdin is not read on dry-run
+		if arg.source == ArgSourceStdin && arg.V == nil {
+			sb.WriteString(fmt.Sprintf("'-' → %s (%s, stdin, ok)\n", arg.signName(), kind))
+			continue
+		}
+
+		vals := arg.valueStrings()
+		if arg.secret {
+			for i := range vals {
+				vals[i] = secretMask
+			}
+		}
+		if arg.Arrayed {
+			kind += fmt.Sprintf(", %d values", len(vals))
+		}
+
+		result := "ok"
+		if arg.displayFn != nil && !arg.secret {
+			result = "ok: " + arg.displayFn(arg.V)
+		}
+		sb.WriteString(fmt.Sprintf("%s → %s (%s, %s, %s)\n", quoteJoin(vals), arg.signName(), kind, arg.source, result))
+	}
+
+	if len(nags.remaining) > 0 && !nags.validateNum {
+		sb.WriteString(fmt.Sprintf("%s → (extra, ignored)\n", quoteJoin(nags.remaining)))
+	}
+	for _, err := range others {
+		sb.WriteString(fmt.Sprintf("error: %s\n", err.Error()))
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+// ToArgv render the bound argument values to os.Args-style slice, in positional order.
+//
+// arrayed value will be expanded. secret values are masked by "****",
+// unless withSecret is true.
+//
+// it stops at the first unbound argument, since the later values cannot be
+// re-parsed to the same positions.
+func (ags *Arguments) ToArgv(withSecret ...bool) []string {
+	showSecret := len(withSecret) > 0 && withSecret[0]
+
+	argv := make([]string, 0, len(ags.args))
+	for _, arg := range ags.args {
+		if !arg.HasValue() {
+			break
+		}
+
+		for _, s := range arg.valueStrings() {
+			if arg.secret && !showSecret {
+				s = secretMask
+			}
+			argv = append(argv, s)
+		}
+	}
+	return argv
+}
+
+// join the strings with single quote wrapped.
+func quoteJoin(ss []string) string {
+	return "'" + strings.Join(ss, "','") + "'"
+}
+
+// SimpleTokenizer split the line by whitespace
+func SimpleTokenizer(line string) ([]string, error) {
+	return strings.Fields(line), nil
+}
+
+// PosixTokenizer split the line like POSIX shell.
+//
+// support single quote, double quote and backslash escape. eg:
+//
+//	`a "b c" 'd e' f\ g` => [a, b c, d e, f g]
+func PosixTokenizer(line string) ([]string, error) {
+	var args []string
+	var quote rune
+	var inToken, escaped bool
+	var sb strings.Builder
+
+	for _, r := range line {
+		if escaped {
+			sb.WriteRune(r)
+			escaped = false
+			continue
+		}
+
+		switch {
+		case r == '\\' && quote != '\'':
+			escaped, inToken = true, true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				sb.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote, inToken = r, true
+		case r == ' ' || r == '\t' || r == '\n' || r == '\r':
+			if inToken {
+				args = append(args, sb.String())
+				sb.Reset()
+				inToken = false
+			}
+		default:
+			sb.WriteRune(r)
+			inToken = true
+		}
+	}
+
+	if escaped {
+		return nil, errorx.Raw("unexpected end of input after escape char '\\'")
+	}
+	if quote != 0 {
+		return nil, errorx.Rawf("unterminated quote %c in the input line", quote)
+	}
+
+	if inToken {
+		args = append(args, sb.String())
+	}
+	return args, nil
+}
+
+// check the arrayed argument values count is in the range. see Argument.WithArgCount()
+func (ags *Arguments) checkArgCount() error {
+	for _, arg := range ags.args {
+		if !arg.hasCount {
+			continue
+		}
+
+		// the argument without value counts as 0, so the min is applied to the optional argument too
+		var got int
+		if arg.countOnly {
+			got, _ = arg.V.(int)
+		} else if arg.HasValue() {
+			got = len(arg.valueStrings())
+		}
+		var err error
+		if got < arg.minCount {
+			err = &ArgParseError{
+				Kind:     ArgErrMissing,
+				ArgName:  arg.Name,
+				Position: arg.index,
+				Err:      errorx.Rawf("argument '%s' requires at least %d values, got %d", arg.ShowName, arg.minCount, got),
+			}
+		} else if arg.maxCount >= 0 && got > arg.maxCount {
+			err = &ArgParseError{
+				Kind:     ArgErrTooMany,
+				ArgName:  arg.Name,
+				Position: arg.index,
+				Err:      errorx.Rawf("argument '%s' accepts at most %d values, got %d", arg.ShowName, arg.maxCount, got),
+			}
+		}
+
+		if err = ags.fail(err); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// check the arrayed argument values count is equals to the referenced argument value.
+func (ags *Arguments) checkCountEqual() error {
+	for _, arg := range ags.args {
+		if arg.countEqualTo == "" || !arg.HasValue() {
+			continue
+		}
+
+		if err := ags.fail(arg.invalidErr(ags.countEqualErr(arg))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// check the arrayed values count equals to the int value of the referenced argument. see Argument.WithCountEqualTo()
+func (ags *Arguments) countEqualErr(arg *Argument) error {
+	idx, ok := ags.lookupIndex(arg.countEqualTo)
+	if !ok {
+		return errorx.Rawf("argument '%s' count reference a not exists argument '%s'", arg.ShowName, arg.countEqualTo)
+	}
+
+	ref := ags.args[idx]
+	want, err := mathutil.ToInt(ref.Val())
+	if err != nil {
+		return errorx.Rawf("argument '%s' value must be an integer for check count of argument '%s'", ref.ShowName, arg.ShowName)
+	}
+
+	if got := len(arg.valueStrings()); got != want {
+		return errorx.Rawf("argument '%s' requires %d values(equals to argument '%s'), got %d", arg.ShowName, want, ref.ShowName, got)
+	}
+	return nil
+}
+
+// check stdin is a terminal
+func stdinIsTerminal() bool {
+	return envutil.IsTerminal(os.Stdin.Fd())
+}
+
+// confirm the bound argument value if it requires confirmation.
+func (ags *Arguments) confirmArg(arg *Argument) error {
+	if arg.confirmMsg == "" || ags.assumeYes || !arg.HasValue() {
+		return nil
+	}
+
+	if !stdinIsTerminal() {
+		return errorx.Rawf("the argument '%s' requires confirmation, but stdin is not a terminal", arg.ShowName)
+	}
+
+	if !interact.Confirm(arg.confirmMsg, false) {
+		return errorx.Rawf("the argument '%s' value is not confirmed, aborted", arg.ShowName)
+	}
+	return nil
+}
+
+/*************************************************************
+ * command arguments
+ *************************************************************/
+
+// AddArg binding a named argument for the command.
+//
+// Notice:
+//   - Required argument cannot be defined after optional argument
+//   - Only one array parameter is allowed
+//   - The (array) argument of multiple values can only be defined at the end
+//
+// Usage:
+//
+//	cmd.AddArg("name", "description")
+//	cmd.AddArg("name", "description", true) // required
+//	cmd.AddArg("names", "description", true, true) // required and is arrayed
+func (ags *Arguments) AddArg(name, desc string, requiredAndArrayed ...bool) *Argument {
+	newArg := NewArgument(name, desc, requiredAndArrayed...)
+	return ags.AddArgument(newArg)
+}
+
+// AddReserved add a reserved argument slot, it keeps the positions of the subsequent arguments stable.
+//
+// the reserved argument is optional, its value is stored without validation, and displayed as "[reserved]" on help.
+func (ags *Arguments) AddReserved(name string) *Argument {
+	newArg := NewArgument(name, "reserved for future use")
+	newArg.reserved = true
+	return ags.AddArgument(newArg)
+}
+
+// AddArgByRule add an arg by simple string rule.
+//
+// simple rule format: "desc;required;default"
+//
+//	ags.AddArgByRule("name", "the user name;required")
+//	ags.AddArgByRule("level", "the log level;false;info")
+//
+// typed rule format: "TYPE;required;desc=DESC;default=VAL;min=N;max=N"
+//
+// the first item is type, allowed: int, float, bool, string, []string. it can also be written as "type=TYPE".
+// the other items can be in any order, "min" and "max" only for int and float.
+// the bare type is typed only if the rule has any KEY=VAL item, so "string;required" is a simple rule.
+//
+//	ags.AddArgByRule("port", "int;required;desc=Port number;min=1;max=65535")
+//	ags.AddArgByRule("files", "type=[]string;desc=the files")
+//
+// will panic on the typed rule is malformed.
+func (ags *Arguments) AddArgByRule(name, rule string) *Argument {
+	items := strutil.SplitTrimmed(rule, ";")
+	if len(items) > 0 {
+		if typ, ok := typedRuleType(items); ok {
+			if !argRuleTypes.Has(typ) {
+				panicf("invalid rule type '%s' for argument '%s', allowed: %s", typ, name, strings.Join(argRuleTypes, ", "))
+			}
+			return ags.addArgByTypedRule(name, typ, items[1:])
+		}
+	}
+
+	for _, item := range items {
+		if key, _, ok := strings.Cut(item, "="); ok && argRuleKeys.Has(strings.TrimSpace(key)) {
+			panicf("invalid rule for argument '%s', the typed rule item '%s' must after a type", name, item)
+		}
+	}
+
+	mp := parseSimpleRule(name, rule)
+
+	required := strutil.QuietBool(mp["required"])
+	newArg := NewArgument(name, mp["desc"], required)
+
+	if defVal := mp["default"]; defVal != "" {
+		newArg.Set(defVal)
+		newArg.source = ArgSourceDefault
+		newArg.defVal, newArg.hasDefault = defVal, !required
+	}
+
+	return ags.AddArgument(newArg)
+}
+
+// allowed type names of the typed argument rule
+var argRuleTypes = arrutil.Strings{"int", "float", "bool", "string", "[]string"}
+
+// the keys of the typed rule items
+var argRuleKeys = arrutil.Strings{"desc", "default", "min", "max"}
+
+// get the type of the typed rule. the bare type is typed only if has any KEY=VAL item.
+func typedRuleType(items []string) (string, bool) {
+	if strings.HasPrefix(items[0], "type=") {
+		return strings.TrimSpace(items[0][5:]), true
+	}
+
+	if argRuleTypes.Has(items[0]) {
+		for _, item := range items[1:] {
+			if strings.Contains(item, "=") {
+				return items[0], true
+			}
+		}
+	}
+	return "", false
+}
+
+// add an argument by typed rule. see AddArgByRule()
+func (ags *Arguments) addArgByTypedRule(name, typ string, items []string) *Argument {
+	var required bool
+	var desc, defVal, minVal, maxVal string
+	for _, item := range items {
+		if item == "required" {
+			required = true
+			continue
+		}
+
+		nodes := strings.SplitN(item, "=", 2)
+		if len(nodes) != 2 {
+			panicf("invalid rule item '%s' for argument '%s', must match `KEY=VAL` or 'required'", item, name)
+		}
+
+		key, val := strings.TrimSpace(nodes[0]), strings.TrimSpace(nodes[1])
+		switch key {
+		case "desc":
+			desc = val
+		case "default":
+			defVal = val
+		case "min":
+			minVal = val
+		case "max":
+			maxVal = val
+		default:
+			panicf("invalid rule key '%s' for argument '%s'", key, name)
+		}
+	}
+
+	newArg := NewArgument(name, desc, required, typ == "[]string")
+	switch typ {
+	case "int", "float":
+		newArg.addValidator(numRangeValidator(newArg, typ, minVal, maxVal))
+	case "bool":
+		newArg.AsBool()
+	}
+
+	if typ != "int" && typ != "float" && (minVal != "" || maxVal != "") {
+		panicf("the rule key 'min' and 'max' only allow for type int and float, argument '%s'", name)
+	}
+
+	ags.AddArgument(newArg)
+	if defVal != "" {
+		if err := newArg.bindFrom(defVal, ArgSourceDefault); err != nil {
+			panicf("invalid default value for argument '%s': %s", name, err.Error())
+		}
+		newArg.defVal, newArg.hasDefault = defVal, !required
+	}
+	return newArg
+}
+
+// make a validator for convert value to int or float, and check the value range
+func numRangeValidator(a *Argument, typ, minVal, maxVal string) func(any) (any, error) {
+	var min, max float64
+	var err error
+	if minVal != "" {
+		if min, err = strconv.ParseFloat(minVal, 64); err != nil {
+			panicf("invalid rule value 'min=%s' for argument '%s'", minVal, a.Name)
+		}
+	}
+	if maxVal != "" {
+		if max, err = strconv.ParseFloat(maxVal, 64); err != nil {
+			panicf("invalid rule value 'max=%s' for argument '%s'", maxVal, a.Name)
+		}
+	}
+
+	checkRange := func(num float64, s string) error {
+		if minVal != "" && num < min {
+			return errorx.Rawf("argument '%s' value must be >= %s, got %s", a.ShowName, minVal, s)
+		}
+		if maxVal != "" && num > max {
+			return errorx.Rawf("argument '%s' value must be <= %s, got %s", a.ShowName, maxVal, s)
+		}
+		return nil
+	}
+
+	if typ == "int" {
+		return func(val any) (any, error) {
+			return convEach(val, func(s string) (int, error) {
+				iv, err := parseNum[int](a, s)
+				if err != nil {
+					return 0, err
+				}
+				return iv, checkRange(float64(iv), s)
+			})
+		}
+	}
+
+	return func(val any) (any, error) {
+		return convEach(val, func(s string) (float64, error) {
+			fv, err := parseNum[float64](a, s)
+			if err != nil {
+				return 0, err
+			}
+			return fv, checkRange(fv, s)
+		})
+	}
+}
+
+// BindArg alias of the AddArgument()
+func (ags *Arguments) BindArg(arg *Argument) *Argument {
+	return ags.AddArgument(arg)
+}
+
+// AddArgument binding a named argument for the command.
+//
+// Notice:
+//   - Required argument cannot be defined after optional argument
+//   - Only one array parameter is allowed
+//   - The (array) argument of multiple values can only be defined at the end
+func (ags *Arguments) AddArgument(arg *Argument) *Argument {
+	if ags.argsIndexes == nil {
+		ags.argsIndexes = make(map[string]int)
+	}
+
+	// validate argument name
+	name := arg.goodArgument(ags.nameValidator)
+	if _, has := ags.argsIndexes[name]; has {
+		panicf("the argument name '%s' already exists in command '%s'", name, ags.name)
+	}
+
+	if ags.hasArrayArg && !ags.bindByName {
+		if ags.argSep == "" {
+			panicf("have defined an array argument, you cannot add argument '%s'", name)
+		}
+
+		// start a new argument group
+		ags.hasArrayArg, ags.hasOptionalArg = false, false
+	}
+
+	if arg.Required && ags.hasOptionalArg && !ags.bindByName {
+		panicf("required argument '%s' cannot be defined after optional argument", name)
+	}
+
+	// add argument index record
+	arg.index = len(ags.args)
+	ags.argsIndexes[name] = arg.index
+	for _, alias := range arg.aliases {
+		ags.addAlias(arg, alias)
+	}
+
+	// add argument
+	arg.owner = ags
+	ags.args = append(ags.args, arg)
+	if !arg.Required {
+		ags.hasOptionalArg = true
+	}
+
+	if arg.Arrayed {
+		ags.hasArrayArg = true
+	}
+
+	return arg
+}
+
+// register an alias name for the argument
+func (ags *Arguments) addAlias(arg *Argument, alias string) {
+	if fn := ags.nameValidator; fn != nil && !fn(alias) {
+		panicf("the argument alias '%s' is invalid, it is rejected by the custom name validator", alias)
+	} else if fn == nil && !goodName.MatchString(alias) {
+		panicf("the argument alias '%s' is invalid, must match: %s", alias, regGoodName)
+	}
+
+	if _, has := ags.argsIndexes[alias]; has {
+		panicf("the argument alias '%s' already exists in command '%s'", alias, ags.name)
+	}
+	ags.argsIndexes[alias] = arg.index
+}
+
+// CheckArgs check the structural problems of the arguments definition, without panic.
+// it's useful for validate a dynamically built arguments(eg: fields changed after added).
+// returns ArgErrors with all problems:
+//   - the arrayed argument is not the last one (allowed on the separator is set)
+//   - required argument after optional argument (checked per group on the separator is set)
+//   - duplicate argument names or aliases
+//   - required argument has a default value (eg: set Required after WithDefault())
+//
+// only the duplicate names and defaults are checked on bind by name. see SetBindByName()
+func (ags *Arguments) CheckArgs() error {
+	var errs ArgErrors
+	seen := make(map[string]bool, len(ags.args))
+	var optional, arrayed *Argument
+	for _, arg := range ags.args {
+		for _, name := range append([]string{arg.Name}, arg.aliases...) {
+			if seen[name] {
+				errs = append(errs, errorx.Rawf("the argument name '%s' is duplicated", name))
+			}
+			seen[name] = true
+		}
+
+		if arg.Required && arg.hasDefault {
+			errs = append(errs, errorx.Rawf("the required argument '%s' cannot have a default value", arg.Name))
+		}
+
+		if ags.bindByName {
+			continue
+		}
+
+		if arrayed != nil {
+			if ags.argSep == "" {
+				errs = append(errs, errorx.Rawf("the arrayed argument '%s' must be the last one, but followed by '%s'", arrayed.Name, arg.Name))
+			} else {
+				// start a new group
+				optional = nil
+			}
+			arrayed = nil
+		}
+
+		if arg.Required && optional != nil {
+			errs = append(errs, errorx.Rawf("required argument '%s' cannot be defined after optional argument '%s'", arg.Name, optional.Name))
+		}
+
+		if !arg.Required && optional == nil {
+			optional = arg
+		}
+		if arg.Arrayed {
+			arrayed = arg
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// LintDefinition check the arguments definition, returns warnings about potentially confusing layouts.
+//
+// it's advisory, can be used on testing. checks:
+//   - multiple optional scalar arguments in a row
+//   - optional scalar argument before an arrayed argument
+func (ags *Arguments) LintDefinition() []string {
+	var warns []string
+	for i, arg := range ags.args {
+		if i == 0 || arg.Required {
+			continue
+		}
+
+		prev := ags.args[i-1]
+		if prev.Required || prev.Arrayed {
+			continue
+		}
+
+		if arg.Arrayed {
+			warns = append(warns, fmt.Sprintf("optional argument '%s' before the arrayed argument '%s', binding intent is unclear", prev.Name, arg.Name))
+		} else {
+			warns = append(warns, fmt.Sprintf("multiple optional arguments in a row: '%s', '%s', binding intent is unclear", prev.Name, arg.Name))
+		}
+	}
+	return warns
+}
+
+// BindToStruct write the bound argument values into the tagged fields of the struct.
+//
+// the tag value is the argument name, values will be converted to the field type.
+// unbound arguments are skipped.
+//
+// Usage:
+//
+//	type Opts struct {
+//		Src   string   `pos:"src"`
+//		Files []string `pos:"files"`
+//	}
+//
+//	err := cmd.BindToStruct(&opts, "pos")
+func (ags *Arguments) BindToStruct(ptr any, tag string) error {
+	nameOf := func(sf reflect.StructField) string {
+		if name := sf.Tag.Get(tag); name != "-" {
+			return name
+		}
+		return ""
+	}
+
+	return walkStruct(ptr, "bind", nameOf, func(sf reflect.StructField, fv reflect.Value, name string) error {
+		idx, ok := ags.lookupIndex(name)
+		if !ok {
+			return errorx.Rawf("the field '%s' tag references an unknown argument '%s'", sf.Name, name)
+		}
+		return fillField(fv, sf.Name, ags.args[idx])
+	})
+}
+
+// FillStruct write the bound argument values into the matched fields of the struct.
+//
+// the field is matched by the name of the "arg" tag, or the lowercased field name if no tag.
+// the unmatched fields and unbound arguments are skipped, the field with tag "arg:\"-\"" is skipped.
+//
+// Usage:
+//
+//	type Opts struct {
+//		Src  string `arg:"src"`
+//		Port int    // match the argument "port"
+//	}
+//
+//	err := cmd.FillStruct(&opts)
+func (ags *Arguments) FillStruct(ptr any) error {
+	return walkStruct(ptr, "fill", argTagName, func(sf reflect.StructField, fv reflect.Value, name string) error {
+		if idx, ok := ags.lookupIndex(name); ok {
+			return fillField(fv, sf.Name, ags.args[idx])
+		}
+		return nil
+	})
+}
+
+// BindStruct register the arguments from the exported fields of the struct,
+// and populate the fields from the bound values after parse successful.
+//
+// the "arg" tag format is "name,required,arrayed", the name default is the lowercased field name.
+// the field with tag "arg:\"-\"" is skipped. the "desc" tag is the description,
+// the "validate" tag is the rule for WithValidateRule(), the type of the int and float field is implied.
+//
+// allow field types: string, int, bool, float and slices thereof. the slice field is arrayed.
+//
+// Usage:
+//
+//	type Opts struct {
+//		Host  string   `arg:"host,required" desc:"the host name"`
+//		Port  int      `arg:"port" validate:"min=1,max=65535"`
+//		Files []string `arg:"files,arrayed"`
+//	}
+//
+//	err := cmd.BindStruct(&opts)
+func (ags *Arguments) BindStruct(ptr any) error {
+	return walkStruct(ptr, "bind", argTagName, func(sf reflect.StructField, fv reflect.Value, name string) error {
+		arg := NewArgument(name, sf.Tag.Get("desc"))
+		_, opts, _ := strings.Cut(sf.Tag.Get("arg"), ",")
+		for _, opt := range strings.Split(opts, ",") {
+			switch strings.TrimSpace(opt) {
+			case "required":
+				arg.Required = true
+			case "arrayed":
+				arg.Arrayed = true
+			}
+		}
+
+		kind := sf.Type.Kind()
+		if kind == reflect.Slice {
+			arg.Arrayed = true
+			kind = sf.Type.Elem().Kind()
+		}
+
+		switch kind {
+		case reflect.String:
+		case reflect.Bool:
+			arg.AsBool()
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			arg.WithType("int")
+		case reflect.Float32, reflect.Float64:
+			arg.WithType("float")
+		default:
+			return errorx.Rawf("unsupported type %s of the field '%s' for bind argument", sf.Type, sf.Name)
+		}
+
+		// the min/max of the rule is value range for the int and float field
+		if rule := sf.Tag.Get("validate"); rule != "" {
+			if typ := arg.Type(); typ != "" {
+				rule = typ + "," + rule
+			}
+			arg.WithValidateRule(rule)
+		}
+
+		ags.AddArgument(arg)
+		ags.structFields = append(ags.structFields, structField{name: sf.Name, fv: fv, arg: arg})
+		return nil
+	})
+}
+
+// walk the exported fields of the struct pointer, the field is skipped if nameOf returns empty.
+func walkStruct(ptr any, action string, nameOf func(sf reflect.StructField) string, fn func(sf reflect.StructField, fv reflect.Value, name string) error) error {
+	rv := reflect.ValueOf(ptr)
+	if rv.Kind() != reflect.Ptr || rv.Elem().Kind() != reflect.Struct {
+		return errorx.Rawf("must provide a pointer to struct for %s arguments", action)
+	}
+
+	rv = rv.Elem()
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		sf := rt.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+
+		if name := nameOf(sf); name != "" {
+			if err := fn(sf, rv.Field(i), name); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// get the argument name of the field by the "arg" tag, default is the lowercased field name.
+func argTagName(sf reflect.StructField) string {
+	tag := sf.Tag.Get("arg")
+	if tag == "-" {
+		return ""
+	}
+
+	name, _, _ := strings.Cut(tag, ",")
+	if name = strings.TrimSpace(name); name == "" {
+		name = strings.ToLower(sf.Name)
+	}
+	return name
+}
+
+// fill the bound value of the argument to the struct field, and mark the argument is read.
+func fillField(fv reflect.Value, fieldName string, arg *Argument) error {
+	arg.read = true
+	if !arg.HasValue() {
+		return nil
+	}
+
+	if err := setFieldValue(fv, arg.Val()); err != nil {
+		return errorx.Rawf("cannot set argument '%s' value to field '%s': %s", arg.ShowName, fieldName, err.Error())
+	}
+	return nil
+}
+
+// Signature get the usage fragment of the arguments in positional order.
+// the required argument is wrapped by "<>", optional by "[]", and the arrayed is suffixed "...".
+//
+// eg: <name> <file> [tags...]
+func (ags *Arguments) Signature() string {
+	ss := make([]string, len(ags.args))
+	for i, arg := range ags.args {
+		ss[i] = arg.signName()
+	}
+	return strings.Join(ss, " ")
+}
+
+// MarkdownTable render the arguments definition as a markdown table. hidden arguments are omitted.
+//
+// columns: Name, Required, Arrayed, Default, Description.
+// the Choices column is added before Description if any argument has choices.
+func (ags *Arguments) MarkdownTable() string {
+	withChoices := false
+	for _, arg := range ags.args {
+		if arg.Visible() && len(arg.choices) > 0 {
+			withChoices = true
+			break
+		}
+	}
+
+	var sb strings.Builder
+	if withChoices {
+		sb.WriteString("| Name | Required | Arrayed | Default | Choices | Description |\n")
+		sb.WriteString("|------|----------|---------|---------|---------|-------------|\n")
+	} else {
+		sb.WriteString("| Name | Required | Arrayed | Default | Description |\n")
+		sb.WriteString("|------|----------|---------|---------|-------------|\n")
+	}
+
+	yesNo := map[bool]string{true: "yes", false: "no"}
+	for _, arg := range ags.args {
+		if !arg.Visible() {
+			continue
+		}
+
+		sb.WriteString(fmt.Sprintf(
+			"| %s | %s | %s | %s | ",
+			mdEscape(ags.ArgHelpName(arg)),
+			yesNo[arg.Required],
+			yesNo[arg.Arrayed],
+			mdEscape(arg.helpDefault()),
+		))
+		if withChoices {
+			sb.WriteString(mdEscape(strings.Join(arg.choices, ", ")) + " | ")
+		}
+		sb.WriteString(mdEscape(arg.Desc) + " |\n")
+	}
+	return sb.String()
+}
+
+// escape the markdown table cell text
+func mdEscape(s string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(s, "|", "\\|"), "\n", " ")
+}
+
+// Sources get the value sources of all arguments. key is argument name.
+func (ags *Arguments) Sources() map[string]ArgSource {
+	mp := make(map[string]ArgSource, len(ags.args))
+	for _, arg := range ags.args {
+		mp[arg.Name] = arg.source
+	}
+	return mp
+}
+
+// DefinitionHash compute a stable hash over the arguments definition.
+//
+// it can be used to detect accidental mutation of the definition at runtime.
+// all the fields that affect the parse are hashed, the runtime values are ignored,
+// and the funcs are only counted.
+func (ags *Arguments) DefinitionHash() string {
+	h := sha256.New()
+	_, _ = fmt.Fprintf(h, "%t|%t|%t|%t|%d|%q|%q|%t|%q|%q\n",
+		ags.validateNum, ags.allowInline, ags.bindByName, ags.arrayLazy, ags.validateMode,
+		ags.argSep, ags.terminator, ags.noTerminator, ags.captureSentinel, ags.captureName,
+	)
+
+	for _, arg := range ags.args {
+		_, _ = fmt.Fprintf(h, "%d|%q|%q|%q|%t|%t|%t|%t|%t\n",
+			arg.index, arg.Name, arg.ShowName, arg.Desc,
+			arg.Required, arg.Arrayed, arg.Hidden, arg.positionalOnly, arg.secret,
+		)
+		_, _ = fmt.Fprintf(h, "%q|%q|%q|%t|%#v|%q|%q|%q|%t|%t|%d|%d|%t\n",
+			arg.typ, arg.choices, arg.aliases, arg.hasDefault, arg.defVal, arg.envName, arg.envSep, arg.ctxKey,
+			arg.stdin, arg.hasCount, arg.minCount, arg.maxCount, arg.countOnly,
+		)
+		_, _ = fmt.Fprintf(h, "%q|%t|%t|%t|%t|%t|%t|%t|%q|%q|%q|%t|%#v|%d|%d|%q\n",
+			arg.countEqualTo, arg.raw, arg.fileExpand, arg.unique, arg.sorted, arg.splitCSV, arg.intern,
+			arg.reserved, arg.osList, arg.deprecated, arg.confirmMsg, arg.hasFallback, arg.coerceFallback,
+			arg.retryTimes, arg.asyncLimit, arg.retryPrompt,
+		)
+		_, _ = fmt.Fprintf(h, "%t|%t|%t|%d|%d|%d|%t\n",
+			arg.Validator != nil, arg.Handler != nil, arg.requiredIf != nil,
+			len(arg.validators), len(arg.normalizers), len(arg.handlers), arg.collectErrs,
+		)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// CloneWithValues clone the arguments definitions and the current bound values.
+//
+// the values are deep copied(slices, maps), the funcs are shallow copied.
+func (ags *Arguments) CloneWithValues() *Arguments {
+	nags := *ags
+	nags.args = make([]*Argument, len(ags.args))
+	nags.argsIndexes = make(map[string]int, len(ags.argsIndexes))
+	for name, idx := range ags.argsIndexes {
+		nags.argsIndexes[name] = idx
+	}
+
+	nags.relations = append([]func(ags *Arguments) error(nil), ags.relations...)
+	for i, arg := range ags.args {
+		narg := *arg
+		narg.Value = structs.NewValue(deepCopyValue(arg.Val()))
+		narg.normalizers = append([]func(s string) (string, error)(nil), arg.normalizers...)
+		narg.handlers = append([]func(val any) any(nil), arg.handlers...)
+		narg.validators = append([]func(val any, ags *Arguments) (any, error)(nil), arg.validators...)
+		narg.owner = &nags
+		nags.args[i] = &narg
+	}
+	return &nags
+}
+
+// BashCompletionSnippet generate a bash completion function for the positional arguments.
+//
+// each argument offers the candidates of its completer(called with empty prefix on generate) or choices
+// at the right positional index. the trailing arrayed argument offers file completion by default.
+// the words start with "-" are skipped on count positions.
+//
+// Usage:
+//
+//	// generate
+//	snippet := cmd.BashCompletionSnippet("myapp deploy")
+//	// use it in a bash completion script: _myapp_deploy_args
+func (ags *Arguments) BashCompletionSnippet(cmdPath string) string {
+	words := strings.Fields(cmdPath)
+	fnName := "_" + regexp.MustCompile(`\W+`).ReplaceAllString(strings.Join(words, "_"), "_") + "_args"
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("# bash completion for the arguments of: %s\n", cmdPath))
+	sb.WriteString(fnName + "() {\n")
+	sb.WriteString("    local cur=\"${COMP_WORDS[COMP_CWORD]}\"\n")
+	sb.WriteString("    local pos=0 i\n")
+	sb.WriteString(fmt.Sprintf("    for ((i=%d; i<COMP_CWORD; i++)); do\n", len(words)))
+	sb.WriteString("        [[ \"${COMP_WORDS[i]}\" == -* ]] || ((pos++))\n")
+	sb.WriteString("    done\n")
+	sb.WriteString("    case $pos in\n")
+
+	for _, arg := range ags.args {
+		var list []string
+		if arg.Visible() {
+			list = arg.candidates("", ags)
+		}
+
+		pattern := strconv.Itoa(arg.index)
+		if arg.Arrayed {
+			pattern = "*"
+		}
+
+		if len(list) > 0 {
+			sb.WriteString(fmt.Sprintf("        %s) COMPREPLY=($(compgen -W %s -- \"$cur\")) ;;\n", pattern, "'"+strings.ReplaceAll(strings.Join(list, " "), "'", `'\''`)+"'"))
+		} else if arg.Arrayed {
+			sb.WriteString(fmt.Sprintf("        %s) COMPREPLY=($(compgen -f -- \"$cur\")) ;;\n", pattern))
+		} else {
+			sb.WriteString(fmt.Sprintf("        %s) COMPREPLY=() ;;\n", pattern))
+		}
+	}
+
+	sb.WriteString("    esac\n")
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+// ParseResultJSON render the parse outcome of each argument as JSON, call it after ParseArgs().
+// the secret value will be masked.
+//
+// eg: [{"name":"src","value":"a.txt","source":"input","defaulted":false}]
+func (ags *Arguments) ParseResultJSON() ([]byte, error) {
+	type argResult struct {
+		Name      string    `json:"name"`
+		Value     any       `json:"value"`
+		Source    ArgSource `json:"source"`
+		Defaulted bool      `json:"defaulted"`
+	}
+
+	list := make([]argResult, 0, len(ags.args))
+	for _, arg := range ags.args {
+		val := arg.V
+		if arg.secret && val != nil {
+			// mask each element, keep the arrayed value is an array
+			if rv := reflect.ValueOf(val); rv.Kind() == reflect.Slice {
+				masks := make([]string, rv.Len())
+				for i := range masks {
+					masks[i] = secretMask
+				}
+				val = masks
+			} else {
+				val = secretMask
+			}
+		}
+
+		list = append(list, argResult{
+			Name:      arg.Name,
+			Value:     val,
+			Source:    arg.source,
+			Defaulted: arg.source == ArgSourceDefault,
+		})
+	}
+	return json.Marshal(list)
+}
+
+// argDefinition the JSON description of an argument definition. see Arguments.Schema()
+type argDefinition struct {
+	Name     string   `json:"name"`
+	Desc     string   `json:"desc"`
+	ShowName string   `json:"show_name"`
+	Required bool     `json:"required"`
+	Arrayed  bool     `json:"arrayed"`
+	Secret   bool     `json:"secret,omitempty"`
+	Choices  []string `json:"choices,omitempty"`
+	Default  any      `json:"default,omitempty"`
+}
+
+// Schema render the definition of each argument as JSON, in positional order.
+// the default value of the secret argument will be masked.
+// it can be loaded by LoadSchema() for regenerate the equivalent arguments.
+//
+// eg: [{"name":"src","desc":"the source","show_name":"src","required":true,"arrayed":false}]
+func (ags *Arguments) Schema() ([]byte, error) {
+	list := make([]argDefinition, 0, len(ags.args))
+	for _, arg := range ags.args {
+		def := argDefinition{
+			Name:     arg.Name,
+			Desc:     arg.Desc,
+			ShowName: arg.ShowName,
+			Required: arg.Required,
+			Arrayed:  arg.Arrayed,
+			Secret:   arg.secret,
+			Choices:  arg.choices,
+		}
+
+		if arg.hasDefault {
+			def.Default = arg.defVal
+			if arg.secret {
+				def.Default = secretMask
+			}
+		}
+		list = append(list, def)
+	}
+	return json.Marshal(list)
+}
+
+// LoadSchema add the arguments by the JSON definitions, it's the reverse of Schema().
+// the masked default value of the secret argument is ignored.
+//
+// the definitions are checked on a clone first, returns error and nothing is added
+// on any definition is invalid(eg: bad name, required after optional).
+func (ags *Arguments) LoadSchema(bs []byte) error {
+	var list []argDefinition
+	if err := json.Unmarshal(bs, &list); err != nil {
+		return errorx.Rawf("invalid arguments schema: %s", err.Error())
+	}
+
+	if err := ags.CloneWithValues().addDefinitions(list); err != nil {
+		return err
+	}
+	return ags.addDefinitions(list)
+}
+
+// add the arguments by the definitions, the panic on add argument is returned as error.
+func (ags *Arguments) addDefinitions(list []argDefinition) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = errorx.Raw(strings.TrimPrefix(fmt.Sprint(r), "GCli: "))
+		}
+	}()
+
+	for _, def := range list {
+		arg := NewArgument(def.Name, def.Desc, def.Required, def.Arrayed)
+		arg.ShowName = def.ShowName
+		if def.Secret {
+			arg.WithSecret()
+		}
+		if len(def.Choices) > 0 {
+			arg.WithChoices(def.Choices)
+		}
+		if def.Default != nil && !(def.Secret && def.Default == secretMask) {
+			arg.WithDefault(def.Default)
+		}
+		ags.AddArgument(arg)
+	}
+	return nil
+}
+
+// ArgErrKind the kind of the argument parse error
+type ArgErrKind uint8
+
+// the kinds of the argument parse error
+const (
+	// ArgErrMissing the required argument is missing
+	ArgErrMissing ArgErrKind = iota + 1
+	// ArgErrTooMany entered too many arguments
+	ArgErrTooMany
+	// ArgErrInvalid the argument value is invalid
+	ArgErrInvalid
+)
+
+// ArgParseError the structured error returned by ParseArgs()
+//
+// Usage:
+//
+//	var pe *gcli.ArgParseError
+//	if errors.As(err, &pe) && pe.Kind == gcli.ArgErrMissing {
+//		os.Exit(2)
+//	}
+type ArgParseError struct {
+	Kind ArgErrKind
+	// ArgName the argument name. it's empty on Kind is ArgErrTooMany
+	ArgName string
+	// Position the argument index, or the position of the first extra arg on Kind is ArgErrTooMany
+	Position int
+	// Err the cause error
+	Err error
+}
+
+// Error message
+func (e *ArgParseError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap the cause error
+func (e *ArgParseError) Unwrap() error {
+	return e.Err
+}
+
+// make the error for entered too many arguments
+func tooManyErr(pos int, extra []string) error {
+	return &ArgParseError{
+		Kind:     ArgErrTooMany,
+		Position: pos,
+		Err:      errorx.Rawf("entered too many arguments: %v", extra),
+	}
+}
+
+// ArgSchema an external schema for validate the bound arguments. see Arguments.ValidateAgainst()
+type ArgSchema struct {
+	Args []ArgSchemaItem `json:"args"`
+}
+
+// ArgSchemaItem the schema of an argument
+type ArgSchemaItem struct {
+	Name     string `json:"name"`
+	Required bool   `json:"required"`
+	// Type allow: string, int, float, bool. empty is not check.
+	Type string `json:"type"`
+	// Min, Max the value range for int and float, the length range for string.
+	Min *float64 `json:"min"`
+	Max *float64 `json:"max"`
+	// Pattern the value must match the regex pattern
+	Pattern string `json:"pattern"`
+	// Choices the value must be one of the choices
+	Choices []string `json:"choices"`
+}
+
+// ArgErrors multi argument errors
+type ArgErrors []error
+
+// Unwrap the errors, for errors.Is() and errors.As() on go1.20+
+func (es ArgErrors) Unwrap() []error {
+	return es
+}
+
+// Is reports whether any error matches the target, for errors.Is() before go1.20
+func (es ArgErrors) Is(target error) bool {
+	for _, err := range es {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As finds the first error that matches the target, for errors.As() before go1.20
+func (es ArgErrors) As(target any) bool {
+	for _, err := range es {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// Error string, each error is on a line
+func (es ArgErrors) Error() string {
+	ss := make([]string, len(es))
+	for i, err := range es {
+		ss[i] = err.Error()
+	}
+	return strings.Join(ss, "\n")
+}
+
+// ValidateAgainst validate the bound arguments against the schema, returns ArgErrors if has errors.
+// for the arrayed argument, each element is checked.
+func (ags *Arguments) ValidateAgainst(schema ArgSchema) error {
+	var errs ArgErrors
+	for _, item := range schema.Args {
+		idx, ok := ags.lookupIndex(item.Name)
+		if !ok {
+			errs = append(errs, errorx.Rawf("argument '%s' is not defined", item.Name))
+			continue
+		}
+
+		arg := ags.args[idx]
+		if !arg.HasValue() {
+			if item.Required {
+				errs = append(errs, errorx.Rawf("argument '%s' is required", item.Name))
+			}
+			continue
+		}
+
+		for _, s := range arg.valueStrings() {
+			if err := item.check(s); err != nil {
+				errs = append(errs, errorx.Rawf("argument '%s' %s", item.Name, err.Error()))
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// check the string value by the schema item
+func (item *ArgSchemaItem) check(s string) error {
+	num := float64(len(s))
+	switch item.Type {
+	case "", "string":
+	case "int":
+		iv, err := parseNum[int](nil, s)
+		if err != nil {
+			return err
+		}
+		num = float64(iv)
+	case "float":
+		fv, err := parseNum[float64](nil, s)
+		if err != nil {
+			return err
+		}
+		num = fv
+	case "bool":
+		if _, err := strconv.ParseBool(s); err != nil {
+			return errorx.Rawf("expects a bool, got %q", s)
+		}
+	default:
+		return errorx.Rawf("has unknown schema type %q", item.Type)
+	}
+
+	if item.Min != nil && num < *item.Min {
+		return errorx.Rawf("value %q is less than min %v", s, *item.Min)
+	}
+	if item.Max != nil && num > *item.Max {
+		return errorx.Rawf("value %q is greater than max %v", s, *item.Max)
+	}
+
+	if item.Pattern != "" {
+		reg, err := regexp.Compile(item.Pattern)
+		if err != nil {
+			return errorx.Rawf("has invalid schema pattern %q", item.Pattern)
+		}
+		if !reg.MatchString(s) {
+			return errorx.Rawf("value %q must match: %s", s, item.Pattern)
+		}
+	}
+
+	if len(item.Choices) > 0 && !arrutil.StringsHas(item.Choices, s) {
+		return errorx.Rawf("value %q must be one of: %s", s, strings.Join(item.Choices, ", "))
+	}
+	return nil
+}
+
+// Args get all defined argument. returns a copy of the list, use EachArg() for iterate them.
+func (ags *Arguments) Args() []*Argument {
+	if len(ags.args) == 0 {
+		return nil
+	}
+	return append([]*Argument(nil), ags.args...)
+}
+
+// EachArg iterate the defined arguments in positional order, stop on the fn returns false.
+//
+// Usage:
+//
+//	ags.EachArg(func(i int, a *gcli.Argument) bool {
+//		fmt.Println(i, a.Name)
+//		return true
+//	})
+func (ags *Arguments) EachArg(fn func(i int, a *Argument) bool) {
+	for i, arg := range ags.args {
+		if !fn(i, arg) {
+			break
+		}
+	}
+}
+
+// check has numeric typed argument. see Argument.WithType()
+func (ags *Arguments) hasNumericArg() bool {
+	for _, arg := range ags.args {
+		if arg.typ == "int" || arg.typ == "float" {
+			return true
+		}
+	}
+	return false
+}
+
+// get the names of the arguments, the positional-only arguments are excluded.
+func (ags *Arguments) names() []string {
+	ns := make([]string, 0, len(ags.args))
+	for _, arg := range ags.args {
+		if !arg.positionalOnly {
+			ns = append(ns, arg.Name)
+		}
+	}
+	return ns
+}
+
+// HasArg check named argument is defined
+func (ags *Arguments) HasArg(name string) bool {
+	_, ok := ags.lookupIndex(name)
+	return ok
+}
+
+// lookup the argument index by name. will exclude the positional-only argument.
+func (ags *Arguments) lookupIndex(name string) (int, bool) {
+	i, ok := ags.argsIndexes[name]
+	if !ok || ags.args[i].positionalOnly {
+		return 0, false
+	}
+	return i, true
+}
+
+// HasArgs defined. alias of the HasArguments()
+func (ags *Arguments) HasArgs() bool {
+	return len(ags.argsIndexes) > 0
+}
+
+// HasArguments defined
+func (ags *Arguments) HasArguments() bool {
+	return len(ags.argsIndexes) > 0
+}
+
+// Arg get arg by defined name.
+//
+// Usage:
+//
+//	intVal := ags.Arg("name").Int()
+//	strVal := ags.Arg("name").String()
+//	arrVal := ags.Arg("names").Array()
+func (ags *Arguments) Arg(name string) *Argument {
+	i, ok := ags.argsIndexes[name]
+	if !ok {
+		panicf("get not exists argument '%s'", name)
+	}
+
+	if ags.args[i].positionalOnly {
+		panicf("the argument '%s' is positional-only, please get it by index", name)
+	}
+
+	ags.args[i].read = true
+	return ags.args[i]
+}
+
+// LookupArg find the argument by name, without panic. the match order:
+//   - exact match the name or alias
+//   - case-insensitive match
+//   - unique prefix match
+//
+// returns false if not found or the match is ambiguous.
+func (ags *Arguments) LookupArg(name string) (*Argument, bool) {
+	if i, ok := ags.lookupIndex(name); ok {
+		ags.args[i].read = true
+		return ags.args[i], true
+	}
+	if name == "" {
+		return nil, false
+	}
+
+	matchers := []func(key string) bool{
+		func(key string) bool { return strings.EqualFold(key, name) },
+		func(key string) bool { return strings.HasPrefix(key, name) },
+	}
+
+	for _, match := range matchers {
+		found := -1
+		for key := range ags.argsIndexes {
+			i, ok := ags.lookupIndex(key)
+			if !ok || !match(key) || i == found {
+				continue
+			}
+
+			// ambiguous
+			if found >= 0 {
+				return nil, false
+			}
+			found = i
+		}
+
+		if found >= 0 {
+			ags.args[found].read = true
+			return ags.args[found], true
+		}
+	}
+	return nil, false
+}
+
+// ArgByIndex get named arg by index
+func (ags *Arguments) ArgByIndex(i int) *Argument {
+	if i >= len(ags.args) {
+		panicf("get not exists argument #%d", i)
+	}
+
+	ags.args[i].read = true
+	return ags.args[i]
+}
+
+// UnreadArgs get the names of the arguments that are never read by Arg(), ArgByIndex(),
+// LookupArg() or Argument.GetValue(), or bound into a struct by BindToStruct(), FillStruct() and BindStruct().
+// it's useful for find the dead argument definitions on debug mode.
+func (ags *Arguments) UnreadArgs() []string {
+	var names []string
+	for _, arg := range ags.args {
+		if !arg.read {
+			names = append(names, arg.Name)
+		}
+	}
+	return names
+}
+
+/*************************************************************
+ * Argument definition
+ *************************************************************/
+
+// Argument a command argument definition
+type Argument struct {
+	*structs.Value
+	// Name argument name. it's required
+	Name string
+	// Desc argument description message
+	Desc string
+	// Type name. eg: string, int, array
+	// Type string
+
+	// ShowName is a name for display help. default is equals to Name.
+	ShowName string
+	// Required arg is required
+	Required bool
+	// Arrayed if is array, can allow to accept multi values, and must in last.
+	Arrayed bool
+	// Hidden the argument on render help
+	Hidden bool
+
+	// Handler custom argument value handler on call GetValue()
+	Handler func(val any) any
+	// more handlers pipeline, will call them after the Handler. see AddHandler()
+	handlers []func(val any) any
+	// more validators chain, will call them after the Validator. see AddValidator()
+	// the context validator is called with the owner arguments. see WithContextValidator()
+	validators []func(val any, ags *Arguments) (any, error)
+	// run all validators and collect the errors. see SetCollectErrors()
+	collectErrs bool
+	// Validator you can add a validator, will call it on binding argument value
+	Validator func(val any) (any, error)
+	// the argument position index in all arguments(cmd.args[index])
+	index int
+	// intern equal string values of an arrayed argument on binding
+	intern bool
+	// completer provide completion candidates for the argument
+	completer func(prefix string, ags *Arguments) []string
+	// confirm message. if not empty, will prompt y/N after value bound
+	confirmMsg string
+	// secret mark the argument value is sensitive
+	secret bool
+	// the arrayed values count must be equals to the int value of the named argument
+	countEqualTo string
+	// normalizers for the string value(or each element), will call them before validate
+	normalizers []func(s string) (string, error)
+	// max tries and prompt message for re-prompt on bind failure in interactive mode
+	retryTimes  int
+	retryPrompt string
+	// source of the bound value
+	source ArgSource
+	// the key for fallback to the value in parse context
+	ctxKey string
+	// the argument can only be accessed by index, not by name
+	positionalOnly bool
+	// the owner arguments, set on AddArgument()
+	owner *Arguments
+	// max concurrency for run async validator on arrayed elements
+	asyncLimit int
+	// fallback value on coerce(validate) the input value failed
+	coerceFallback any
+	hasFallback    bool
+	// logger for the value binding lifecycle of the argument
+	valueLogger func(stage, detail string)
+	// render the value for humans. see WithDisplayFunc()
+	displayFn func(val any) string
+	// the argument is only supported on these OSes(runtime.GOOS). empty is all.
+	osList []string
+	// split each token of the arrayed argument by comma, in CSV-aware mode
+	splitCSV bool
+	// the default value for bind on the argument is absent. see WithDefault()
+	defVal     any
+	hasDefault bool
+	// the argument is a reserved slot. see Arguments.AddReserved()
+	reserved bool
+	// the allowed values of the argument. see WithChoices()
+	choices []string
+	// the env var name and list separator for fallback value. see WithEnv()
+	envName string
+	envSep  string
+	// read the value from stdin on the input token is "-"
+	stdin bool
+	// the arrayed values count range. see WithArgCount()
+	minCount, maxCount int
+	hasCount           bool
+	// the alias names for lookup the argument. see WithAlias()
+	aliases []string
+	// bind the values verbatim, skip the normalizers, validators and handlers. see WithRaw()
+	raw bool
+	// expand the "@file" token to the file contents. see WithFileExpand()
+	fileExpand bool
+	// remove duplicate elements and sort the arrayed values. see WithUnique(), WithSorted()
+	unique bool
+	sorted bool
+	// the value type hint. see WithType()
+	typ string
+	// mark the argument has been read. see Arguments.UnreadArgs()
+	read bool
+	// the deprecation message and mark the warning has been emitted. see WithDeprecated()
+	deprecated string
+	warned     bool
+	// bind the count of values instead of the values. see WithCountOnly()
+	countOnly bool
+	// the predicate for the argument is required. see WithRequiredIf()
+	requiredIf func(ags *Arguments) bool
+}
+
+// ArgSource the source of an argument value
+type ArgSource string
+
+// the argument value sources
+const (
+	// ArgSourceNone the value is not set
+	ArgSourceNone ArgSource = ""
+	// ArgSourceInput from the positional input args
+	ArgSourceInput ArgSource = "input"
+	// ArgSourceDefault from the default value
+	ArgSourceDefault ArgSource = "default"
+	// ArgSourceEnv from the environment variable
+	ArgSourceEnv ArgSource = "env"
+	// ArgSourceStdin read from the stdin
+	ArgSourceStdin ArgSource = "stdin"
+	// ArgSourceContext from the parse context. see Arguments.ParseArgsWithContext()
+	ArgSourceContext ArgSource = "context"
+	// ArgSourcePrompt from the interactive prompt
+	ArgSourcePrompt ArgSource = "prompt"
+	// ArgSourceSet set by programmatic. eg: SetValue()
+	ArgSourceSet ArgSource = "set"
+)
+
+// secretMask the mask string for display secret value
+const secretMask = "****"
+
+// NewArg quick create a new command argument
+func NewArg(name, desc string, val any, requiredAndArrayed ...bool) *Argument {
+	var arrayed, required bool
+	if ln := len(requiredAndArrayed); ln > 0 {
+		required = requiredAndArrayed[0]
+		if ln > 1 {
+			arrayed = requiredAndArrayed[1]
+		}
+	}
+
+	return &Argument{
+		Name:  name,
+		Desc:  desc,
+		Value: structs.NewValue(val),
+		// other settings
+		// ShowName: name,
+		Required: required,
+		Arrayed:  arrayed,
+	}
+}
+
+// NewArgument quick create a new command argument
+func NewArgument(name, desc string, requiredAndArrayed ...bool) *Argument {
+	return NewArg(name, desc, nil, requiredAndArrayed...)
+}
+
+// WithHidden settings the argument is hidden on render help
+func (a *Argument) WithHidden() *Argument {
+	a.Hidden = true
+	return a
+}
+
+// Visible check the argument is visible on render help
+func (a *Argument) Visible() bool {
+	return !a.Hidden && a.supportedOS()
+}
+
+// OnlyOnOS mark the argument is only supported on the given OSes(compare with runtime.GOOS).
+// bind value to it on other OSes will return error, the fallback values are skipped,
+// and it is hidden on help.
+//
+// eg: OnlyOnOS("linux", "darwin")
+func (a *Argument) OnlyOnOS(osNames ...string) *Argument {
+	a.osList = osNames
+	return a
+}
+
+// check the argument is supported on the current OS
+func (a *Argument) supportedOS() bool {
+	return len(a.osList) == 0 || arrutil.StringsHas(a.osList, runtime.GOOS)
+}
+
+// IsReserved check the argument is a reserved slot. see Arguments.AddReserved()
+func (a *Argument) IsReserved() bool {
+	return a.reserved
+}
+
+// WithAlias add alias names for lookup the argument by Arg(), HasArg() and so on.
+// the aliases do not affect the positional parsing and help display.
+//
+// eg: WithAlias("file") // after renamed the "file" to "path"
+func (a *Argument) WithAlias(names ...string) *Argument {
+	for _, name := range names {
+		a.aliases = append(a.aliases, name)
+		if a.owner != nil {
+			a.owner.addAlias(a, name)
+		}
+	}
+	return a
+}
+
+// Aliases get the alias names of the argument
+func (a *Argument) Aliases() []string {
+	return a.aliases
+}
+
+// SetPositionalOnly mark the argument can only be accessed by index, not by name.
+// it still binds by position, and displayed as "ARG{index}" on help
+func (a *Argument) SetPositionalOnly() *Argument {
+	a.positionalOnly = true
+	return a
+}
+
+// SetArrayed the argument
+func (a *Argument) SetArrayed() *Argument {
+	a.Arrayed = true
+	return a
+}
+
+// WithValue to the argument
+func (a *Argument) WithValue(val any) *Argument {
+	a.Value.Set(val)
+	return a
+}
+
+// WithDefault set the default value, will bind it on the argument is absent in the input args.
+// the default value is also passed to the Validator and Handler, and displayed on help.
+//
+// NOTE: the required argument cannot have a default value, will panic.
+func (a *Argument) WithDefault(val any) *Argument {
+	if a.Required {
+		panicf("the required argument '%s' cannot have a default value", a.Name)
+	}
+
+	a.defVal, a.hasDefault = val, true
+	return a
+}
+
+// WithDeprecated mark the argument is deprecated, the value still binds normally.
+// on the value bound from the input, will print the message once. see Arguments.SetDeprecationOutput()
+//
+//	cmd.AddArg("mode", "desc").WithDeprecated("use the option --mode instead")
+func (a *Argument) WithDeprecated(msg string) *Argument {
+	a.deprecated = msg
+	return a
+}
+
+// IsDeprecated check the argument is deprecated. see WithDeprecated()
+func (a *Argument) IsDeprecated() bool {
+	return a.deprecated != ""
+}
+
+// DeprecatedMsg get the deprecation message. see WithDeprecated()
+func (a *Argument) DeprecatedMsg() string {
+	return a.deprecated
+}
+
+// WithRequiredIf the argument is required only when the fn returns true.
+// the fn is called after all arguments bound, the error is same as the static Required.
+//
+// the static Required is same as WithRequiredIf(always true), but it's checked on binding.
+// NOTE: the argument is treated as optional for the positional ordering,
+// so the arguments after it cannot be static required.
+//
+// Usage:
+//
+//	cmd.AddArg("mode", "desc")
+//	cmd.AddArg("target", "desc").WithRequiredIf(func(ags *gcli.Arguments) bool {
+//		return ags.Arg("mode").String() == "deploy"
+//	})
+func (a *Argument) WithRequiredIf(fn func(ags *Arguments) bool) *Argument {
+	a.requiredIf = fn
+	return a
+}
+
+// WithEnv set the env var for fallback value, on the argument is absent in the input args.
+//
+// value precedence: input > env > default. for the arrayed argument, the env value will be
+// split by the listSep, default is the OS path list separator(":" or ";").
+//
+// Usage:
+//
+//	cmd.AddArg("token", "desc", true).WithEnv("APP_TOKEN")
+//	cmd.AddArg("paths", "desc", false, true).WithEnv("APP_PATHS", ",")
+func (a *Argument) WithEnv(envName string, listSep ...string) *Argument {
+	a.envName = envName
+	a.envSep = string(os.PathListSeparator)
+	if len(listSep) > 0 {
+		a.envSep = listSep[0]
+	}
+	return a
+}
+
+// WithStdin read the whole stdin as the value on the input token is "-".
+// for the arrayed argument, the stdin is split into lines, and the "-" must be the only value.
+// it's applied on all the parse entries, eg: ParseArgs(), ParseArgsMap({"content": "-"}), "content=-".
+//
+// NOTE: the stdin can only be read once on parse, and must be piped.
+//
+// Usage:
+//
+//	cmd.AddArg("content", "desc").WithStdin()
+//	// cat file.txt | app process -
+func (a *Argument) WithStdin() *Argument {
+	a.stdin = true
+	return a
+}
+
+// Default get the default value. see WithDefault()
+func (a *Argument) Default() any {
+	return a.defVal
+}
+
+// WithFn a func for config the argument
+func (a *Argument) WithFn(fn func(arg *Argument)) *Argument {
+	if fn != nil {
+		fn(a)
+	}
+	return a
+}
+
+// WithIntern enable interning for an arrayed argument values.
+//
+// On binding, equal strings will share one backing instance, it can reduce
+// memory when receiving a huge number of repeated values.
+// NOTE: it trades CPU (a map lookup per value) for memory.
+func (a *Argument) WithIntern() *Argument {
+	a.intern = true
+	return a
+}
+
+// WithCompleter set a completion candidates provider of the argument
+func (a *Argument) WithCompleter(fn func(prefix string, ags *Arguments) []string) *Argument {
+	a.completer = fn
+	return a
+}
+
+// Complete get the completion candidates of the argument, which have the prefix.
+// the completer receives the owner arguments, fallback to the choices if no completer.
+func (a *Argument) Complete(prefix string) []string {
+	var list []string
+	for _, s := range a.candidates(prefix, a.owner) {
+		if strings.HasPrefix(s, prefix) {
+			list = append(list, s)
+		}
+	}
+	return list
+}
+
+// RequireConfirm mark the argument value requires confirmation.
+//
+// On parse, when the value is bound from any source(input, env, default...) and stdin is a terminal,
+// will prompt y/N after all checks passed, and abort with an error if not confirmed. see Arguments.SetAssumeYes()
+func (a *Argument) RequireConfirm(prompt string) *Argument {
+	a.confirmMsg = prompt
+	return a
+}
+
+// AsBool coerce the argument value to bool. for arrayed argument, will coerce each element.
+//
+// accepted forms(case-insensitive): 1/0, yes/no, on/off, true/false
+//
+// Usage:
+//
+//	cmd.AddArg("enable", "desc").AsBool()
+//	// after parsed
+//	cmd.Arg("enable").Bool()
+func (a *Argument) AsBool() *Argument {
+	return a.addValidator(func(val any) (any, error) {
+		return convEach(val, func(s string) (bool, error) {
+			switch strings.ToLower(strings.TrimSpace(s)) {
+			case "1", "yes", "on", "true":
+				return true, nil
+			case "0", "no", "off", "false":
+				return false, nil
+			}
+			return false, errorx.Rawf("argument '%s' expects a bool value(1/0, yes/no, on/off, true/false), got %q", a.ShowName, s)
+		})
+	})
+}
+
+// Bools get bool values of the arrayed argument. see AsBool()
+func (a *Argument) Bools() []bool {
+	if bs, ok := a.V.([]bool); ok {
+		return bs
+	}
+	return nil
+}
+
+// WithChoices limit the value(or each element) must be one of the choices.
+// the choices are also used as completion candidates if no completer.
+//
+// Usage:
+//
+//	cmd.AddArg("action", "desc").WithChoices([]string{"start", "stop", "restart"})
+//	// case-insensitive
+//	cmd.AddArg("action", "desc").WithChoices([]string{"start", "stop"}, true)
+func (a *Argument) WithChoices(choices []string, ignoreCase ...bool) *Argument {
+	a.choices = choices
+	fold := len(ignoreCase) > 0 && ignoreCase[0]
+
+	return a.addValidator(func(val any) (any, error) {
+		return convEach(val, func(s string) (string, error) {
+			for _, c := range choices {
+				if s == c || fold && strings.EqualFold(s, c) {
+					return s, nil
+				}
+			}
+			return "", errorx.Rawf("argument '%s' must be one of: %s", a.ShowName, strings.Join(choices, ", "))
+		})
+	})
+}
+
+// Choices get the allowed values of the argument. see WithChoices()
+func (a *Argument) Choices() []string {
+	return a.choices
+}
+
+// WithBlocklist reject the value(or each element) if it is one of the blocked values. case-sensitive.
+//
+// eg: WithBlocklist("admin", "root")
+func (a *Argument) WithBlocklist(blocked ...string) *Argument {
+	return a.addValidator(blocklistValidator(a, blocked, false))
+}
+
+// WithBlocklistFold like WithBlocklist(), but compare the values with case-insensitive.
+func (a *Argument) WithBlocklistFold(blocked ...string) *Argument {
+	return a.addValidator(blocklistValidator(a, blocked, true))
+}
+
+// make a validator for check the value is not blocked
+func blocklistValidator(a *Argument, blocked []string, fold bool) func(any) (any, error) {
+	return func(val any) (any, error) {
+		return convEach(val, func(s string) (string, error) {
+			for _, b := range blocked {
+				if s == b || fold && strings.EqualFold(s, b) {
+					return "", errorx.Rawf("argument '%s' value %q is not allowed", a.ShowName, s)
+				}
+			}
+			return s, nil
+		})
+	}
+}
+
+// AsFloatLocale convert the value(or each element) to float64, with the locale decimal separator.
+// the grouping separator is "." if decimalSep is ",", otherwise is ",". spaces are also ignored.
+//
+// Usage:
+//
+//	cmd.AddArg("price", "desc").AsFloatLocale(",")
+//	// input: 1.234,5
+//	cmd.Arg("price").Float() // 1234.5
+func (a *Argument) AsFloatLocale(decimalSep string) *Argument {
+	groupSep := ","
+	if decimalSep == "," {
+		groupSep = "."
+	}
+
+	replacer := strings.NewReplacer(groupSep, "", " ", "", decimalSep, ".")
+	return a.addValidator(func(val any) (any, error) {
+		return convEach(val, func(s string) (float64, error) {
+			fv, err := strconv.ParseFloat(replacer.Replace(strings.TrimSpace(s)), 64)
+			if err != nil {
+				return 0, errorx.Rawf("argument '%s' expects a float(decimal separator %q), got %q", a.ShowName, decimalSep, s)
+			}
+			return fv, nil
+		})
+	})
+}
+
+// Float get the float64 value. see AsFloatLocale()
+func (a *Argument) Float() float64 {
+	return a.Float64()
+}
+
+// WithSecret mark the argument value is sensitive, will be masked on display.
+//
+// the value is masked by "****" on String(), help default, ToArgv() and ParseResultJSON(),
+// the real value still can be got by GetValue().
+func (a *Argument) WithSecret() *Argument {
+	a.secret = true
+	return a
+}
+
+// IsSecret check the argument value is sensitive
+func (a *Argument) IsSecret() bool {
+	return a.secret
+}
+
+// WithCountEqualTo the arrayed argument values count must be equals to
+// the int value of another argument. will check it after all arguments bound.
+//
+// Usage:
+//
+//	// split <n> <items...>
+//	cmd.AddArg("n", "desc", true)
+//	cmd.AddArg("items", "desc", true, true).WithCountEqualTo("n")
+func (a *Argument) WithCountEqualTo(otherArgName string) *Argument {
+	if !a.Arrayed {
+		panicf("the argument '%s' must be arrayed for check values count", a.Name)
+	}
+
+	a.countEqualTo = otherArgName
+	return a
+}
+
+// WithSplitCSV split each input token of the arrayed argument by comma, quoted segments are preserved.
+// the splitting follows the CSV rules: the double quote in a quoted segment is escaped by doubling it.
+//
+// eg: `a,"b,c",d` => [a b,c d], `"say ""hi""",x` => [say "hi" x]
+func (a *Argument) WithSplitCSV() *Argument {
+	if !a.Arrayed {
+		panicf("the argument '%s' must be arrayed for split values", a.Name)
+	}
+
+	a.splitCSV = true
+	return a
+}
+
+// WithArgCount limit the values count of the arrayed argument, max < 0 is unbounded.
+// the min > 0 is checked even on the argument is optional and without any value.
+//
+// eg: WithArgCount(2, 5) // between 2 and 5 values
+func (a *Argument) WithArgCount(min, max int) *Argument {
+	if !a.Arrayed {
+		panicf("the argument '%s' must be arrayed for limit values count", a.Name)
+	}
+	if max >= 0 && max < min {
+		panicf("the argument '%s' values count max(%d) cannot be less than min(%d)", a.Name, max, min)
+	}
+
+	a.minCount, a.maxCount, a.hasCount = min, max, true
+	return a
+}
+
+// WithCountOnly bind the count of the values of the arrayed argument, the raw values are discarded.
+// the count can be got by Int() after parsed, it's also checked by WithArgCount().
+//
+//	cmd.AddArg("verbose", "desc", false, true).WithCountOnly()
+//	// input: v v v
+//	cmd.Arg("verbose").Int() // 3
+func (a *Argument) WithCountOnly() *Argument {
+	if !a.Arrayed {
+		panicf("the argument '%s' must be arrayed for count values", a.Name)
+	}
+
+	a.countOnly = true
+	return a
+}
+
+// WithRaw bind the values of the arrayed argument verbatim, the normalizers, validators and
+// handlers are skipped. and the separator token(see Arguments.SetArgSeparator()) after its
+// group start is not treated specially.
+//
+// it's useful for pass through the args to a child process. eg: run <script> [args...]
+func (a *Argument) WithRaw() *Argument {
+	if !a.Arrayed {
+		panicf("the argument '%s' must be arrayed for bind raw values", a.Name)
+	}
+
+	a.raw = true
+	return a
+}
+
+// WithFileExpand expand the token starts with "@" to the contents of the referenced file.
+//
+// for the arrayed argument, the token is replaced by the lines of the file, the blank
+// lines and "#" comment lines are skipped. otherwise, binds the full file contents.
+//
+// eg: app add @hosts.txt
+func (a *Argument) WithFileExpand() *Argument {
+	a.fileExpand = true
+	return a
+}
+
+// WithUnique remove the duplicate elements of the arrayed argument, the first-seen order is preserved.
+// it's applied after the validators and handlers. will panic on the argument is not arrayed.
+func (a *Argument) WithUnique() *Argument {
+	if !a.Arrayed {
+		panicf("the argument '%s' must be arrayed for unique values", a.Name)
+	}
+
+	a.unique = true
+	return a
+}
+
+// WithSorted sort the values of the arrayed argument, numbers are compared numerically.
+// it's applied after the validators, handlers and WithUnique(). will panic on the argument is not arrayed.
+func (a *Argument) WithSorted() *Argument {
+	if !a.Arrayed {
+		panicf("the argument '%s' must be arrayed for sort values", a.Name)
+	}
+
+	a.sorted = true
+	return a
+}
+
+// WithType set the value type hint, allow: int, float, string.
+// the int and float value(or each element) will be converted, and the negative number
+// tokens(eg: -5, -3.14) are treated as the argument values on parse command options.
+func (a *Argument) WithType(kind string) *Argument {
+	switch kind {
+	case "int":
+		a.addValidator(func(val any) (any, error) {
+			return convEach(val, func(s string) (int, error) {
+				return parseNum[int](a, s)
+			})
+		})
+	case "float":
+		a.addValidator(func(val any) (any, error) {
+			return convEach(val, func(s string) (float64, error) {
+				return parseNum[float64](a, s)
+			})
+		})
+	case "string":
+	default:
+		panicf("invalid type '%s' for argument '%s', allow: int, float, string", kind, a.Name)
+	}
+
+	a.typ = kind
+	return a
+}
+
+// Type get the value type hint. see WithType()
+func (a *Argument) Type() string {
+	return a.typ
+}
+
+// WithTrim add a handler to trim the whitespace of the value(or each element).
+func (a *Argument) WithTrim() *Argument {
+	return a.AddHandler(stringsHandler(strings.TrimSpace))
+}
+
+// WithLower add a handler to convert the value(or each element) to lower case.
+func (a *Argument) WithLower() *Argument {
+	return a.AddHandler(stringsHandler(strings.ToLower))
+}
+
+// WithUpper add a handler to convert the value(or each element) to upper case.
+func (a *Argument) WithUpper() *Argument {
+	return a.AddHandler(stringsHandler(strings.ToUpper))
+}
+
+// WithPrefixStrip remove a single leading prefix from the value(or each element) before binding.
+// the value without the prefix is unchanged.
+//
+// eg: WithPrefixStrip("user:") "user:alice" => "alice"
+func (a *Argument) WithPrefixStrip(prefix string) *Argument {
+	return a.addNormalizer(func(s string) (string, error) {
+		return strings.TrimPrefix(s, prefix), nil
+	})
+}
+
+// WithTildeExpand expand the leading "~" or "~/" of the value(or each element) to the user home dir.
+//
+// the embedded tilde is unchanged. it is a normalizer, so it runs before any validators(eg: file exists check).
+//
+// eg: "~/.config" => "/home/inhere/.config"
+func (a *Argument) WithTildeExpand() *Argument {
+	return a.addNormalizer(func(s string) (string, error) {
+		if s != "~" && !strings.HasPrefix(s, "~/") {
+			return s, nil
+		}
+
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		return home + s[1:], nil
+	})
+}
+
+// WithDisplayFunc set a func to render the value for humans, it is used by String(),
+// the default value on help and ExplainParse(). the GetValue() is not affected.
+//
+// Usage:
+//
+//	cmd.AddArg("timeout", "desc").WithValidator(toDuration).WithDisplayFunc(func(val any) string {
+//		return val.(time.Duration).Round(time.Second).String()
+//	})
+func (a *Argument) WithDisplayFunc(fn func(val any) string) *Argument {
+	a.displayFn = fn
+	return a
+}
+
+// WithRetryPrompt re-prompt for input on bind value failure in interactive mode(stdin is a terminal).
+//
+// will re-prompt up to maxTries, finally returns the error if still invalid.
+// non-interactive mode will return the error immediately.
+// for arrayed argument, the input line will be split by whitespace.
+// it's applied on all the parse entries. eg: ParseArgs(), ParseNamedArgs(), ParseArgsMap(), BindArgs()
+func (a *Argument) WithRetryPrompt(maxTries int, prompt string) *Argument {
+	a.retryTimes = maxTries
+	a.retryPrompt = prompt
+	return a
+}
+
+// WithUnicodeNorm normalize the value(or each element) to the unicode normalization form
+// before validate and store. eg: norm.NFC, norm.NFD
+func (a *Argument) WithUnicodeNorm(form norm.Form) *Argument {
+	return a.addNormalizer(func(s string) (string, error) {
+		return form.String(s), nil
+	})
+}
+
+// WithPredicate add a predicate check for the value(or each element of arrayed value).
+// will return an error with errMsg when the predicate is false, the value is unchanged.
+//
+// Usage:
+//
+//	cmd.AddArg("name", "desc").WithPredicate(func(val any) bool {
+//		return len(val.(string)) > 3
+//	}, "the name length must be greater than 3")
+func (a *Argument) WithPredicate(fn func(val any) bool, errMsg string) *Argument {
+	return a.addValidator(func(val any) (any, error) {
+		if rv := reflect.ValueOf(val); rv.Kind() == reflect.Slice {
+			for i := 0; i < rv.Len(); i++ {
+				if !fn(rv.Index(i).Interface()) {
+					return nil, errorx.Raw(errMsg)
+				}
+			}
+		} else if !fn(val) {
+			return nil, errorx.Raw(errMsg)
+		}
+		return val, nil
+	})
+}
+
+// WithMultipleOf check the int value(or each element of arrayed value) is a multiple of step.
+//
+// Usage:
+//
+//	cmd.AddArg("size", "block size").WithMultipleOf(512)
+func (a *Argument) WithMultipleOf(step int64) *Argument {
+	if step <= 0 {
+		panicf("the step for argument '%s' must be greater than 0", a.Name)
+	}
+
+	return a.addValidator(func(val any) (any, error) {
+		_, err := convEach(val, func(s string) (int64, error) {
+			i64, err := parseNum[int64](a, s)
+			if err != nil {
+				return 0, err
+			}
+
+			if i64%step != 0 {
+				return 0, errorx.Rawf("argument '%s' value %d is not a multiple of %d", a.ShowName, i64, step)
+			}
+			return i64, nil
+		})
+
+		if err != nil {
+			return nil, err
+		}
+		return val, nil
+	})
+}
+
+// WithContextDefault the argument will fall back to the ctx[key] value
+// when it is absent on Arguments.ParseArgsWithContext()
+func (a *Argument) WithContextDefault(key string) *Argument {
+	a.ctxKey = key
+	return a
+}
+
+// KV a key-value pair of the pair list argument. see Argument.AsPairList()
+type KV struct {
+	Key, Val string
+}
+
+// AsPairList coerce each element of the arrayed argument to a KV pair, split by kvSep.
+// the order is preserved and allow duplicate keys.
+//
+// Usage:
+//
+//	// input: "name=string age=int"
+//	cmd.AddArg("fields", "desc", true, true).AsPairList("=")
+//	// after parsed
+//	pairs := cmd.Arg("fields").Pairs()
+func (a *Argument) AsPairList(kvSep string) *Argument {
+	return a.addValidator(func(val any) (any, error) {
+		return convEach(val, func(s string) (KV, error) {
+			nodes := strings.SplitN(s, kvSep, 2)
+			if len(nodes) != 2 {
+				return KV{}, errorx.Rawf("argument '%s' element %q must be in format KEY%sVALUE", a.ShowName, s, kvSep)
+			}
+			return KV{Key: nodes[0], Val: nodes[1]}, nil
+		})
+	})
+}
+
+// Pairs get the KV pairs of the argument. see AsPairList()
+func (a *Argument) Pairs() []KV {
+	switch typVal := a.V.(type) {
+	case []KV:
+		return typVal
+	case KV:
+		return []KV{typVal}
+	}
+	return nil
+}
+
+// the argument value decode modes. see Argument.WithDecode()
+const (
+	// DecodeBase64 decode the value by std base64
+	DecodeBase64 uint8 = iota + 1
+	// DecodeGzip decompress the value by gzip
+	DecodeGzip
+	// DecodeBase64Gzip decode the value by base64, then decompress by gzip
+	DecodeBase64Gzip
+)
+
+// WithDecode decode the value(or each element) before validate and store.
+//
+// Usage:
+//
+//	cmd.AddArg("payload", "desc").WithDecode(gcli.DecodeBase64)
+func (a *Argument) WithDecode(mode uint8) *Argument {
+	return a.addNormalizer(func(s string) (string, error) {
+		bs := []byte(s)
+		var err error
+		if mode == DecodeBase64 || mode == DecodeBase64Gzip {
+			if bs, err = base64.StdEncoding.DecodeString(s); err != nil {
+				return "", errorx.Rawf("argument '%s' value is not valid base64: %s", a.ShowName, err.Error())
+			}
+		}
+
+		if mode == DecodeGzip || mode == DecodeBase64Gzip {
+			if bs, err = gunzip(bs); err != nil {
+				return "", errorx.Rawf("argument '%s' value is not valid gzip data: %s", a.ShowName, err.Error())
+			}
+		}
+		return string(bs), nil
+	})
+}
+
+// WithAsyncLimit set max concurrency for run async validator on arrayed elements. default is 4
+func (a *Argument) WithAsyncLimit(n int) *Argument {
+	a.asyncLimit = n
+	return a
+}
+
+// WithAsyncValidator add a validator(eg: check value by remote service) with the parse context.
+//
+// the success results are cached per value for the cacheTTL within the process.
+// for arrayed argument, the elements(in string form for typed slice) are validated concurrently, up to WithAsyncLimit().
+//
+// Usage:
+//
+//	cmd.AddArg("id", "desc").WithAsyncValidator(func(ctx context.Context, val any) (any, error) {
+//		return api.CheckID(ctx, val.(string))
+//	}, time.Minute)
+func (a *Argument) WithAsyncValidator(fn func(ctx context.Context, val any) (any, error), cacheTTL time.Duration) *Argument {
+	cache := newValueCache(cacheTTL)
+
+	call := func(ctx context.Context, s string) (any, error) {
+		if ret, ok := cache.get(s); ok {
+			return ret, nil
+		}
+
+		ret, err := fn(ctx, s)
+		if err != nil {
+			return nil, err
+		}
+
+		cache.set(s, ret)
+		return ret, nil
+	}
+
+	return a.addValidator(func(val any) (any, error) {
+		ctx := context.Background()
+		if a.owner != nil {
+			ctx = a.owner.Context()
+		}
+
+		// the string form of the value, or each element of typed slice
+		strVal, _ := convEach(val, func(s string) (string, error) { return s, nil })
+		ss, ok := strVal.([]string)
+		if !ok {
+			s := strutil.QuietString(val)
+			ret, err := call(ctx, s)
+			if err != nil {
+				return nil, errorx.Rawf("argument '%s' value %q is invalid: %s", a.ShowName, s, err.Error())
+			}
+			return ret, nil
+		}
+
+		limit := a.asyncLimit
+		if limit <= 0 {
+			limit = 4
+		}
+
+		rets := make([]any, len(ss))
+		errs := make([]error, len(ss))
+		sem := make(chan struct{}, limit)
+
+		var wg sync.WaitGroup
+		for i, s := range ss {
+			wg.Add(1)
+			sem <- struct{}{}
+			go func(i int, s string) {
+				defer func() {
+					<-sem
+					wg.Done()
+				}()
+				rets[i], errs[i] = call(ctx, s)
+			}(i, s)
+		}
+		wg.Wait()
+
+		for i, err := range errs {
+			if err != nil {
+				return nil, errorx.Rawf("argument '%s' element #%d %q is invalid: %s", a.ShowName, i, ss[i], err.Error())
+			}
+		}
+		return rets, nil
+	})
+}
+
+// WithCoerceFallback on parse, if coerce(validate) the input value failed and the argument is optional,
+// the def value will be stored and print a warning, instead of failing the parse. see SetDeprecationOutput()
+// required argument still returns the error.
+func (a *Argument) WithCoerceFallback(def any) *Argument {
+	a.coerceFallback = def
+	a.hasFallback = true
+	return a
+}
+
+// AsWritablePath check the path(or each element) is writable, or can be created:
+//   - the path exists and is writable
+//   - or, the parent directory exists and is writable
+//
+// the cleaned absolute path will be stored.
+func (a *Argument) AsWritablePath() *Argument {
+	return a.addValidator(func(val any) (any, error) {
+		return convEach(val, func(s string) (string, error) {
+			absPath, err := filepath.Abs(s)
+			if err != nil {
+				return "", errorx.Rawf("argument '%s' path %q is invalid: %s", a.ShowName, s, err.Error())
+			}
+
+			if err = checkWritable(absPath); err != nil {
+				return "", errorx.Rawf("argument '%s' path %q is not writable: %s", a.ShowName, s, err.Error())
+			}
+			return absPath, nil
+		})
+	})
+}
+
+// WithValidateRule add validators by a simple rule string, items split by ",".
+// it's also used for the struct tag "validate" on bind struct.
+//
+// supported items:
+//
+//	int          - convert value to int
+//	float        - convert value to float64
+//	min=N        - min value for int/float, otherwise is min length of the string
+//	max=N        - max value for int/float, otherwise is max length of the string
+//	choices=a|b  - value must be one of the choices, split by "|"
+//	file         - value must be an exists file path
+//	regex=EXPR   - value must match the regex. NOTE: it must be the last item
+//
+// Usage:
+//
+//	cmd.AddArg("port", "desc").WithValidateRule("int,min=1,max=65535")
+//	cmd.AddArg("name", "desc").WithValidateRule("min=2,regex=^[a-z]+$")
+//
+// will panic on the rule is malformed.
+func (a *Argument) WithValidateRule(rule string) *Argument {
+	var typ, minVal, maxVal string
+	var fns []func(s string) error
+
+	for rule != "" {
+		var item string
+		if strings.HasPrefix(rule, "regex=") {
+			item, rule = rule, ""
+		} else if item, rule, _ = strings.Cut(rule, ","); strings.TrimSpace(item) == "" {
+			continue
+		}
+
+		key, val, _ := strings.Cut(strings.TrimSpace(item), "=")
+		switch key {
+		case "int", "float":
+			typ = key
+		case "min":
+			minVal = val
+		case "max":
+			maxVal = val
+		case "file":
+			fns = append(fns, func(s string) error {
+				if !fsutil.IsFile(s) {
+					return errorx.Rawf("argument '%s' value %q is not an exists file", a.ShowName, s)
+				}
+				return nil
+			})
+		case "choices":
+			choices := strings.Split(val, "|")
+			fns = append(fns, func(s string) error {
+				if !arrutil.StringsHas(choices, s) {
+					return errorx.Rawf("argument '%s' must be one of: %s", a.ShowName, strings.Join(choices, ", "))
+				}
+				return nil
+			})
+		case "regex":
+			reg, err := regexp.Compile(val)
+			if err != nil {
+				panicf("invalid regex rule for argument '%s': %s", a.Name, err.Error())
+			}
+
+			fns = append(fns, func(s string) error {
+				if !reg.MatchString(s) {
+					return errorx.Rawf("argument '%s' value %q must match: %s", a.ShowName, s, val)
+				}
+				return nil
+			})
+		default:
+			panicf("invalid validate rule item '%s' for argument '%s'", item, a.Name)
+		}
+	}
+
+	// string length range
+	if typ == "" && (minVal != "" || maxVal != "") {
+		min, max := 0, -1
+		var err error
+		if minVal != "" {
+			min, err = strconv.Atoi(minVal)
+		}
+		if maxVal != "" && err == nil {
+			max, err = strconv.Atoi(maxVal)
+		}
+
+		if err != nil {
+			panicf("invalid min/max rule for argument '%s', must be integer", a.Name)
+		}
+
+		fns = append(fns, func(s string) error {
+			if ln := len(s); ln < min || (max >= 0 && ln > max) {
+				return errorx.Rawf("argument '%s' value length must be in range %s-%s, got %d", a.ShowName, minVal, maxVal, ln)
+			}
+			return nil
+		})
+	}
+
+	if len(fns) > 0 {
+		a.addValidator(func(val any) (any, error) {
+			_, err := convEach(val, func(s string) (string, error) {
+				for _, fn := range fns {
+					if err := fn(s); err != nil {
+						return "", err
+					}
+				}
+				return s, nil
+			})
+
+			if err != nil {
+				return nil, err
+			}
+			return val, nil
+		})
+	}
+
+	if typ != "" {
+		a.addValidator(numRangeValidator(a, typ, minVal, maxVal))
+	}
+	return a
+}
+
+// WithRewriteFile load a rewrite table from the file, and rewrite the matched value(or each element)
+// on binding. the unmatched values pass through.
+//
+// file format: each line is "OLD=NEW", blank lines and lines start with "#" are ignored.
+//
+// will panic on the file cannot be loaded.
+func (a *Argument) WithRewriteFile(path string) *Argument {
+	table, err := loadRewriteTable(path)
+	if err != nil {
+		panicf("load the rewrite file for argument '%s' error: %s", a.Name, err.Error())
+	}
+
+	return a.addNormalizer(func(s string) (string, error) {
+		if ns, ok := table[s]; ok {
+			return ns, nil
+		}
+		return s, nil
+	})
+}
+
+// WithSliceInvariant add a validator for check an invariant over all elements of the arrayed value.
+// the typed elements(eg: after WithType()) are passed in the string form.
+//
+// Usage:
+//
+//	cmd.AddArg("files", "desc", true, true).WithSliceInvariant(gcli.SameDir())
+func (a *Argument) WithSliceInvariant(fn func(ss []string) error) *Argument {
+	return a.addValidator(func(val any) (any, error) {
+		if val == nil {
+			return val, nil
+		}
+
+		if err := fn(toStrings(val)); err != nil {
+			return nil, errorx.Rawf("argument '%s' %s", a.ShowName, err.Error())
+		}
+		return val, nil
+	})
+}
+
+// SameDir an invariant for WithSliceInvariant(), all elements must be in the same directory
+func SameDir() func(ss []string) error {
+	return sameProperty("be in the same directory", filepath.Dir)
+}
+
+// SameExtension an invariant for WithSliceInvariant(), all elements must have the same file extension
+func SameExtension() func(ss []string) error {
+	return sameProperty("have the same extension", filepath.Ext)
+}
+
+func sameProperty(desc string, getFn func(s string) string) func(ss []string) error {
+	return func(ss []string) error {
+		for i := 1; i < len(ss); i++ {
+			if want, got := getFn(ss[0]), getFn(ss[i]); want != got {
+				return errorx.Rawf("all elements must %s, but %q is %q and %q is %q", desc, ss[0], want, ss[i], got)
+			}
+		}
+		return nil
+	}
+}
+
+// valueError the invalid value error of the builtin validators, will be prefixed with the argument name on validate.
+type valueError struct {
+	msg string
+}
+
+// Error string
+func (e *valueError) Error() string {
+	return e.msg
+}
+
+// create the invalid value error for the builtin validators
+func invalidValue(s, format string, v ...any) error {
+	return &valueError{msg: fmt.Sprintf("value %q ", s) + fmt.Sprintf(format, v...)}
+}
+
+// ValidateIP a validator for WithValidator(), the value(or each element) must be an IPv4 or IPv6 address.
+//
+// Usage:
+//
+//	cmd.AddArg("host", "desc").WithValidator(gcli.ValidateIP())
+func ValidateIP() func(val any) (any, error) {
+	return func(val any) (any, error) {
+		return convEach(val, func(s string) (string, error) {
+			if net.ParseIP(strings.TrimSpace(s)) == nil {
+				return "", invalidValue(s, "is not a valid IP address")
+			}
+			return strings.TrimSpace(s), nil
+		})
+	}
+}
+
+// ValidateCIDR a validator for WithValidator(), the value(or each element) must be a CIDR notation. eg: 10.0.0.0/8
+func ValidateCIDR() func(val any) (any, error) {
+	return func(val any) (any, error) {
+		return convEach(val, func(s string) (string, error) {
+			if _, _, err := net.ParseCIDR(strings.TrimSpace(s)); err != nil {
+				return "", invalidValue(s, "is not a valid CIDR notation")
+			}
+			return strings.TrimSpace(s), nil
+		})
+	}
+}
+
+// ValidatePort a validator for WithValidator(), the value(or each element) must be a port number in 0-65535.
+// the value will be converted to int.
+func ValidatePort() func(val any) (any, error) {
+	return func(val any) (any, error) {
+		return convEach(val, func(s string) (int, error) {
+			port, err := parseNum[int](nil, s)
+			if err != nil || port < 0 || port > 65535 {
+				return 0, invalidValue(s, "is not a valid port, must be in 0-65535")
+			}
+			return port, nil
+		})
+	}
+}
+
+// ValidatePercent a validator for WithValidator(), the value(or each element) must be a percent in 0-100,
+// allow the "%" suffix. eg: 50, 12.5%
+//
+// the value will be converted to float64.
+func ValidatePercent() func(val any) (any, error) {
+	return func(val any) (any, error) {
+		return convEach(val, func(s string) (float64, error) {
+			str := strings.TrimSuffix(strings.TrimSpace(s), "%")
+			pct, err := strconv.ParseFloat(strings.TrimSpace(str), 64)
+			if err != nil || pct < 0 || pct > 100 {
+				return 0, invalidValue(s, "is not a valid percent, must be in 0-100")
+			}
+			return pct, nil
+		})
+	}
+}
+
+// WithValueLogger set a logger for the value binding lifecycle of the argument.
+//
+// stages: normalize, validate, handle, store
+func (a *Argument) WithValueLogger(fn func(stage, detail string)) *Argument {
+	a.valueLogger = fn
+	return a
+}
+
+// AsRegexp compile the value(or each element) to *regexp.Regexp
+//
+// Usage:
+//
+//	cmd.AddArg("pattern", "desc").AsRegexp()
+//	// after parsed
+//	reg := cmd.Arg("pattern").Regexp()
+func (a *Argument) AsRegexp() *Argument {
+	return a.addValidator(func(val any) (any, error) {
+		return convEach(val, func(s string) (*regexp.Regexp, error) {
+			reg, err := regexp.Compile(s)
+			if err != nil {
+				return nil, errorx.Rawf("argument '%s' has invalid regexp %q: %s", a.ShowName, s, err.Error())
+			}
+			return reg, nil
+		})
+	})
+}
+
+// Regexp get the compiled regexp value. see AsRegexp()
+func (a *Argument) Regexp() *regexp.Regexp {
+	if reg, ok := a.V.(*regexp.Regexp); ok {
+		return reg
+	}
+	return nil
+}
+
+// Regexps get the compiled regexp values of the arrayed argument. see AsRegexp()
+func (a *Argument) Regexps() []*regexp.Regexp {
+	if regs, ok := a.V.([]*regexp.Regexp); ok {
+		return regs
+	}
+	return nil
+}
+
+// WithContextValidator add a validator with the owner arguments, it can read the sibling
+// arguments by ags.Arg(). it is appended to the validators chain. see AddValidator()
+//
+// the ags is the arguments being parsed on call, eg: the clone by CloneWithValues().
+//
+// NOTE: on parse, only the earlier positional arguments are guaranteed bound at call time.
+//
+// Usage:
+//
+//	cmd.AddArg("end", "desc").WithContextValidator(func(val any, ags *gcli.Arguments) (any, error) {
+//		if mathutil.QuietInt(val) <= ags.Arg("start").Int() {
+//			return nil, errors.New("the <end> must be greater than <start>")
+//		}
+//		return val, nil
+//	})
+func (a *Argument) WithContextValidator(fn func(val any, ags *Arguments) (any, error)) *Argument {
+	a.validators = append(a.validators, fn)
+	return a
+}
+
+// AddValidator append a validator to the chain, the validators are called in registration order,
+// each validator can transform the value passed to the next. the Validator field is called as the first.
+//
+// Usage:
+//
+//	cmd.AddArg("file", "desc").AddValidator(notEmpty).AddValidator(isFile).AddValidator(isReadable)
+func (a *Argument) AddValidator(fn func(val any) (any, error)) *Argument {
+	return a.addValidator(fn)
+}
+
+// SetCollectErrors run all validators even after a failure, and returns the combined ArgErrors.
+// default is stop at the first error.
+func (a *Argument) SetCollectErrors(collect bool) *Argument {
+	a.collectErrs = collect
+	return a
+}
+
+// AddHandler append a value handler to the pipeline, the handlers are called in registration order.
+// the Handler field is called as the first handler, and the validators run before the pipeline.
+//
+// Usage:
+//
+//	cmd.AddArg("name", "desc").AddHandler(trimFn).AddHandler(lowerFn)
+func (a *Argument) AddHandler(fn func(val any) any) *Argument {
+	a.handlers = append(a.handlers, fn)
+	return a
+}
+
+// WithValidator set a value validator of the argument.
+// it's called before the builtin validators(eg: WithChoices, WithType), they will not be replaced.
+func (a *Argument) WithValidator(fn func(any) (any, error)) *Argument {
+	a.Validator = fn
+	return a
+}
+
+// SetValue set an validated value
+func (a *Argument) SetValue(val any) error {
+	return a.bindFrom(val, ArgSourceSet)
+}
+
+// Init the argument
+func (a *Argument) Init() *Argument {
+	a.goodArgument(nil)
+	return a
+}
+
+// check the argument name, validName is the custom name validator. see Arguments.SetNameValidator()
+func (a *Argument) goodArgument(validName func(name string) bool) string {
+	name := strings.TrimSpace(a.Name)
+	if name == "" {
+		panicf("the command argument name cannot be empty")
+	}
+
+	if validName != nil {
+		if !validName(name) {
+			panicf("the argument name '%s' is invalid, it is rejected by the custom name validator", name)
+		}
+	} else if !goodName.MatchString(name) {
+		panicf("the argument name '%s' is invalid, must match: %s", name, regGoodName)
+	}
+
+	a.Name = name
+	if a.ShowName == "" {
+		a.ShowName = name
+	}
+
+	if a.Value == nil {
+		a.Value = structs.NewValue(nil)
+	}
+	return name
+}
+
+// GetValue get value by custom handler func
+func (a *Argument) GetValue() interface{} {
+	a.read = true
+	return a.handle(a.Value.Val())
+}
+
+// IntE get the int value, returns error on convert failed. will apply the Handler if set.
+// returns 0 and nil error if no value.
+func (a *Argument) IntE() (int, error) {
+	val := a.GetValue()
+	if iv, err := mathutil.ToInt(val); err == nil {
+		return iv, nil
+	}
+	return parseNum[int](a, strutil.QuietString(val))
+}
+
+// Int64E get the int64 value, returns error on convert failed. will apply the Handler if set.
+// returns 0 and nil error if no value.
+func (a *Argument) Int64E() (int64, error) {
+	val := a.GetValue()
+	if i64, err := mathutil.ToInt64(val); err == nil {
+		return i64, nil
+	}
+	return parseNum[int64](a, strutil.QuietString(val))
+}
+
+// FloatE get the float64 value, returns error on convert failed. will apply the Handler if set.
+// returns 0 and nil error if no value.
+func (a *Argument) FloatE() (float64, error) {
+	val := a.GetValue()
+	if fv, err := mathutil.ToFloat(val); err == nil {
+		return fv, nil
+	}
+	return parseNum[float64](a, strutil.QuietString(val))
+}
+
+// Duration get the value as time.Duration, the string value is parsed by time.ParseDuration().
+// will apply the Handler if set. returns 0 and nil error if no value.
+func (a *Argument) Duration() (time.Duration, error) {
+	switch val := a.GetValue().(type) {
+	case nil:
+		return 0, nil
+	case time.Duration:
+		return val, nil
+	case string:
+		d, err := time.ParseDuration(strings.TrimSpace(val))
+		if err != nil {
+			return 0, errorx.Rawf("argument '%s' expects a duration(eg: 30s, 1h5m), got %q", a.ShowName, val)
+		}
+		return d, nil
+	default:
+		return 0, errorx.Rawf("argument '%s' expects a duration(eg: 30s, 1h5m), got %q", a.ShowName, strutil.QuietString(val))
+	}
+}
+
+// Bytes get the value as byte size, the string value allow size suffixes:
+//
+//	B, KB/MB/GB/TB (base 1000), K/M/G/T and KiB/MiB/GiB/TiB (base 1024). eg: 10MB, 2GiB
+//
+// will apply the Handler if set. returns 0 and nil error if no value.
+func (a *Argument) Bytes() (int64, error) {
+	val := a.GetValue()
+	if str, ok := val.(string); ok {
+		size, err := parseByteSize(str)
+		if err != nil {
+			return 0, errorx.Rawf("argument '%s' expects a byte size(eg: 10MB, 2GiB), got %q", a.ShowName, str)
+		}
+		return size, nil
+	}
+
+	size, err := mathutil.ToInt64(val)
+	if err != nil {
+		return 0, errorx.Rawf("argument '%s' expects a byte size(eg: 10MB, 2GiB), got %q", a.ShowName, strutil.QuietString(val))
+	}
+	return size, nil
+}
+
+// Ints get the int values of the arrayed argument. returns error on the argument is not arrayed,
+// or an element is not an integer. returns empty slice if no value.
+func (a *Argument) Ints() ([]int, error) {
+	return elemsOf[int](a, "an integer")
+}
+
+// Floats get the float64 values of the arrayed argument. returns error on the argument is not arrayed,
+// or an element is not a float. returns empty slice if no value.
+func (a *Argument) Floats() ([]float64, error) {
+	return elemsOf[float64](a, "a float")
+}
+
+// convert each element of the arrayed argument value
+func elemsOf[T int | float64](a *Argument, typDesc string) ([]T, error) {
+	if !a.Arrayed {
+		return nil, errorx.Rawf("argument '%s' is not arrayed", a.ShowName)
+	}
+
+	ss := toStrings(a.GetValue())
+	list := make([]T, 0, len(ss))
+	for i, s := range ss {
+		v, err := parseNum[T](a, s)
+		if err != nil {
+			return nil, errorx.Rawf("argument '%s' element #%d %q is not %s", a.ShowName, i, s, typDesc)
+		}
+		list = append(list, v)
+	}
+	return list, nil
 }
 
 // Array alias of the Strings()
@@ -338,37 +4168,753 @@ func (a *Argument) Array() (ss []string) {
 	return a.Strings()
 }
 
+// ChunksOf get the arrayed values grouped into chunks of n.
+// returns error when the values count is not a multiple of n.
+//
+// Usage:
+//
+//	// input: k1 v1 k2 v2
+//	pairs, err := cmd.Arg("kvs").ChunksOf(2) // [[k1 v1] [k2 v2]]
+func (a *Argument) ChunksOf(n int) ([][]string, error) {
+	if n <= 0 {
+		return nil, errorx.Rawf("the chunk size must be greater than 0, got %d", n)
+	}
+
+	ss := a.valueStrings()
+	if len(ss)%n != 0 {
+		return nil, errorx.Rawf("argument '%s' values count %d is not a multiple of %d", a.ShowName, len(ss), n)
+	}
+
+	chunks := make([][]string, 0, len(ss)/n)
+	for i := 0; i < len(ss); i += n {
+		chunks = append(chunks, ss[i:i+n])
+	}
+	return chunks, nil
+}
+
+// String get the value for display. will use the display func if set by WithDisplayFunc()
+//
+// the secret value is masked by "****", use GetValue() to get the real value.
+func (a *Argument) String() string {
+	if a.secret && a.HasValue() {
+		return secretMask
+	}
+	if a.displayFn != nil && a.HasValue() {
+		return a.displayFn(a.V)
+	}
+	return a.Value.String()
+}
+
 // HasValue value is empty
 func (a *Argument) HasValue() bool {
 	return a.V != nil
 }
 
+// ValueSource get the source of the bound value
+func (a *Argument) ValueSource() ArgSource {
+	return a.source
+}
+
+// Source get the source name of the bound value, it's the string form of ValueSource().
+//
+// returns one of: "input", "default", "env", "set", "stdin", "context", "prompt", or "" if unset.
+func (a *Argument) Source() string {
+	return string(a.source)
+}
+
 // Index get argument index in the command
 func (a *Argument) Index() int {
 	return a.index
 }
 
+// convert the value to strings. slice value will be expanded.
+func (a *Argument) valueStrings() []string {
+	return toStrings(a.V)
+}
+
+// stringsHandler create a handler to apply the fn on the string form of the value(or each element).
+func stringsHandler(fn func(s string) string) func(val any) any {
+	return func(val any) any {
+		switch typVal := val.(type) {
+		case nil:
+			return nil
+		case string:
+			return fn(typVal)
+		}
+
+		if reflect.ValueOf(val).Kind() != reflect.Slice {
+			return fn(strutil.QuietString(val))
+		}
+
+		ss := toStrings(val)
+		ns := make([]string, len(ss))
+		for i, s := range ss {
+			ns[i] = fn(s)
+		}
+		return ns
+	}
+}
+
+// convert the value to strings. slice value will be expanded.
+func toStrings(val any) []string {
+	switch typVal := val.(type) {
+	case nil:
+		return nil
+	case []string:
+		return typVal
+	case string:
+		return []string{typVal}
+	}
+
+	rv := reflect.ValueOf(val)
+	if rv.Kind() != reflect.Slice {
+		return []string{strutil.QuietString(val)}
+	}
+
+	ss := make([]string, rv.Len())
+	for i := range ss {
+		ss[i] = strutil.QuietString(rv.Index(i).Interface())
+	}
+	return ss
+}
+
+// default value for display help. secret value will be masked.
+//
+// NOTE: use the value set on definition if no default by WithDefault(). eg: WithValue()
+func (a *Argument) helpDefault() string {
+	if a.hasDefault {
+		if a.secret {
+			return secretMask
+		}
+		return strings.Join(toStrings(a.defVal), ",")
+	}
+
+	if !a.HasValue() {
+		return ""
+	}
+	if a.secret {
+		return secretMask
+	}
+	if a.displayFn != nil {
+		return a.displayFn(a.V)
+	}
+	return strings.Join(a.valueStrings(), ",")
+}
+
+// HelpDesc for render help message, will append the default value if set by WithDefault(),
+// and the deprecation message if set by WithDeprecated().
+func (a *Argument) HelpDesc() string {
+	desc := a.Desc
+	if a.hasDefault {
+		desc += " (default: " + a.helpDefault() + ")"
+	}
+	if a.deprecated != "" {
+		desc += " (deprecated: " + a.deprecated + ")"
+	}
+	return desc
+}
+
+// sign name for display usage. eg: <name>, [name], [names...], <ARG0>
+func (a *Argument) signName() string {
+	name := a.bareName()
+	if a.Arrayed {
+		name += "..."
+	}
+
+	if a.Required {
+		return "<" + name + ">"
+	}
+	return "[" + name + "]"
+}
+
 // HelpName for render help message
 func (a *Argument) HelpName() string {
+	name := a.bareName()
+	if a.reserved {
+		name = "[" + name + "]"
+	} else if a.positionalOnly {
+		name = "<" + name + ">"
+	}
+
 	if a.Arrayed {
-		return a.ShowName + "..."
+		return name + "..."
+	}
+	return name
+}
+
+// the display name without any wrapper and suffix. eg: name, reserved, ARG0
+func (a *Argument) bareName() string {
+	if a.reserved {
+		return "reserved"
+	}
+	if a.positionalOnly {
+		return fmt.Sprintf("ARG%d", a.index)
 	}
 	return a.ShowName
 }
 
+// expand the "@file" tokens of the value. see WithFileExpand()
+func (a *Argument) expandFiles(val any) (any, error) {
+	readFile := func(tok string) ([]byte, error) {
+		bs, err := os.ReadFile(tok[1:])
+		if err != nil {
+			return nil, errorx.Rawf("argument '%s' cannot read the file %q: %s", a.ShowName, tok[1:], err.Error())
+		}
+		return bs, nil
+	}
+
+	switch typVal := val.(type) {
+	case string:
+		if len(typVal) < 2 || typVal[0] != '@' {
+			return val, nil
+		}
+
+		bs, err := readFile(typVal)
+		if err != nil {
+			return nil, err
+		}
+		return string(bs), nil
+	case []string:
+		ss := make([]string, 0, len(typVal))
+		for _, tok := range typVal {
+			if len(tok) < 2 || tok[0] != '@' {
+				ss = append(ss, tok)
+				continue
+			}
+
+			bs, err := readFile(tok)
+			if err != nil {
+				return nil, err
+			}
+
+			for _, line := range strings.Split(string(bs), "\n") {
+				if line = strings.TrimSpace(line); line != "" && line[0] != '#' {
+					ss = append(ss, line)
+				}
+			}
+		}
+		return ss, nil
+	}
+	return val, nil
+}
+
+// completion candidates of the argument, use the choices if no completer.
+func (a *Argument) candidates(prefix string, ags *Arguments) []string {
+	if a.completer != nil {
+		return a.completer(prefix, ags)
+	}
+	return a.choices
+}
+
+// make the error for the required argument is missing
+func (a *Argument) missingErr() error {
+	return &ArgParseError{
+		Kind:     ArgErrMissing,
+		ArgName:  a.Name,
+		Position: a.index,
+		Err:      errorx.Rawf("must set value for the argument: %s(position#%d)", a.ShowName, a.index),
+	}
+}
+
+// wrap the binding error of the argument to ArgParseError with ArgErrInvalid
+func (a *Argument) invalidErr(err error) error {
+	if _, ok := err.(*ArgParseError); ok || err == nil {
+		return err
+	}
+	return &ArgParseError{Kind: ArgErrInvalid, ArgName: a.Name, Position: a.index, Err: err}
+}
+
+// bind the input value to the argument on parse, used by all the parse entries.
+// the "-" reads from stdin(see WithStdin()), will re-prompt on failure(see WithRetryPrompt()),
+// then the bind error is recovered by the coerce fallback value. see WithCoerceFallback()
+// the values of the arrayed argument are merged on append. see ParseArgsAppend()
+func (ags *Arguments) bindInput(arg *Argument, val any) (err error) {
+	prev := arg.V
+	if arg.stdin && isStdinToken(val) {
+		err = ags.bindStdin(arg)
+	} else {
+		err = arg.bindWithRetry(val)
+	}
+
+	if err == nil && ags.appendArray && arg.Arrayed {
+		arg.mergeValues(prev)
+	}
+
+	if err != nil && ags.recoverBindErr(arg, err) {
+		return nil
+	}
+	return err
+}
+
+// merge the new bound values to the prev values of the arrayed argument, then apply the
+// WithUnique() and WithSorted() on the merged values. the count is summed on WithCountOnly().
+func (a *Argument) mergeValues(prev any) {
+	if a.countOnly {
+		n, _ := prev.(int)
+		a.V = n + mathutil.QuietInt(a.V)
+		return
+	}
+
+	val := appendValues(prev, a.V)
+	if a.unique {
+		val = uniqueSlice(val)
+	}
+	if a.sorted {
+		sortSlice(val)
+	}
+	a.V = val
+}
+
+// check the input value is the stdin placeholder "-", or an arrayed value with only it.
+func isStdinToken(val any) bool {
+	switch typVal := val.(type) {
+	case string:
+		return typVal == "-"
+	case []string:
+		return len(typVal) == 1 && typVal[0] == "-"
+	case []any:
+		return len(typVal) == 1 && typVal[0] == "-"
+	}
+	return false
+}
+
+// recover the bind error by the coerce fallback value. returns false if cannot recover.
+func (ags *Arguments) recoverBindErr(arg *Argument, err error) bool {
+	if !arg.hasFallback || arg.Required {
+		return false
+	}
+
+	ags.warnf("argument '%s' use the fallback value %v, the input is invalid: %s", arg.ShowName, arg.coerceFallback, err.Error())
+	arg.V, arg.source = arg.coerceFallback, ArgSourceDefault
+	return true
+}
+
+// bind value, will re-prompt on failure if WithRetryPrompt() is set.
+func (a *Argument) bindWithRetry(val any) error {
+	err := a.bindFrom(val, ArgSourceInput)
+	if err == nil || a.retryTimes <= 0 || !stdinIsTerminal() {
+		return err
+	}
+
+	for i := 0; i < a.retryTimes; i++ {
+		color.Error.Println(err.Error())
+		ans, rErr := interact.ReadLine(a.retryPrompt)
+		if rErr != nil {
+			return rErr
+		}
+
+		if a.Arrayed {
+			err = a.bindFrom(strings.Fields(ans), ArgSourcePrompt)
+		} else {
+			err = a.bindFrom(ans, ArgSourcePrompt)
+		}
+
+		if err == nil {
+			return nil
+		}
+	}
+	return err
+}
+
+// append a string value normalizer
+func (a *Argument) addNormalizer(fn func(s string) (string, error)) *Argument {
+	a.normalizers = append(a.normalizers, fn)
+	return a
+}
+
+// normalize the string value or each element of the strings value.
+func (a *Argument) normalize(val any) (any, error) {
+	if len(a.normalizers) == 0 {
+		return val, nil
+	}
+
+	return convEach(val, func(s string) (string, error) {
+		var err error
+		for _, fn := range a.normalizers {
+			if s, err = fn(s); err != nil {
+				return "", err
+			}
+		}
+		return s, nil
+	})
+}
+
+// append a builtin validator to the chain, keep the Validator field for the user's func.
+func (a *Argument) addValidator(fn func(any) (any, error)) *Argument {
+	a.validators = append(a.validators, func(val any, _ *Arguments) (any, error) {
+		return fn(val)
+	})
+	return a
+}
+
+// bind a value to the argument, and record the value source on success.
+func (a *Argument) bindFrom(val any, src ArgSource) error {
+	if !a.supportedOS() {
+		return errorx.Rawf("the argument '%s' is not supported on %s, only on: %s", a.ShowName, runtime.GOOS, strings.Join(a.osList, ", "))
+	}
+
+	if err := a.bindValue(val); err != nil {
+		return err
+	}
+
+	a.source = src
+	return nil
+}
+
+// restore a decoded value to the argument, the normalizers are skipped. see Arguments.SetValues()
+func (a *Argument) restoreValue(val any) error {
+	if !a.supportedOS() {
+		return errorx.Rawf("the argument '%s' is not supported on %s, only on: %s", a.ShowName, runtime.GOOS, strings.Join(a.osList, ", "))
+	}
+
+	if !a.reserved && !a.raw {
+		var err error
+		if val, err = a.checkValue(val); err != nil {
+			return err
+		}
+	}
+
+	a.Value.V, a.source = val, ArgSourceSet
+	a.logValue("store", "restored value: %v", val)
+	return nil
+}
+
 // bind a value to the argument
 func (a *Argument) bindValue(val any) (err error) {
+	val, err = a.resolveValue(val)
+	if err != nil {
+		return
+	}
+
+	a.Value.V = val
+	a.logValue("store", "stored value: %v", val)
+	return
+}
+
+// log the value binding lifecycle. see WithValueLogger()
+func (a *Argument) logValue(stage, format string, v ...any) {
+	if a.valueLogger != nil {
+		a.valueLogger(stage, fmt.Sprintf(format, v...))
+	}
+}
+
+// resolve the input value by validator and handler, but not store it.
+func (a *Argument) resolveValue(val any) (any, error) {
+	if a.reserved || a.raw {
+		return val, nil
+	}
+
+	val, err := a.decodeValue(val)
+	if err != nil {
+		return nil, err
+	}
+	return a.checkValue(val)
+}
+
+// decode the raw input value: expand the files, split the CSV and run the normalizers.
+func (a *Argument) decodeValue(val any) (any, error) {
+	if a.fileExpand {
+		var err error
+		if val, err = a.expandFiles(val); err != nil {
+			return nil, err
+		}
+	}
+
+	if a.splitCSV {
+		ss, err := splitCSVTokens(val)
+		if err != nil {
+			return nil, errorx.Rawf("argument '%s' has invalid CSV value: %s", a.ShowName, err.Error())
+		}
+		val = ss
+	}
+
+	if a.intern {
+		if ss, ok := val.([]string); ok {
+			val = internStrings(ss)
+		}
+	}
+
+	a.logValue("normalize", "input value: %v", val)
+	val, err := a.normalize(val)
+	if err != nil {
+		a.logValue("normalize", "error: %s", err.Error())
+		return nil, err
+	}
+	return val, nil
+}
+
+// check the decoded value by validator and handler.
+func (a *Argument) checkValue(val any) (any, error) {
+	var err error
+	if a.Validator != nil || len(a.validators) > 0 {
+		a.logValue("validate", "validate value: %v", val)
+		if val, err = a.validate(val); err != nil {
+			a.logValue("validate", "error: %s", err.Error())
+			return nil, err
+		}
+	}
+
+	if a.Handler != nil || len(a.handlers) > 0 {
+		a.logValue("handle", "handle value: %v", val)
+		val = a.handle(val)
+	}
+
+	if a.unique {
+		val = uniqueSlice(val)
+	}
+	if a.sorted {
+		sortSlice(val)
+	}
+	if a.countOnly {
+		val = len(toStrings(val))
+	}
+	return val, nil
+}
+
+// call the Validator and the validators chain in order.
+// will collect all errors to ArgErrors if SetCollectErrors(true), otherwise stop at the first error.
+func (a *Argument) validate(val any) (any, error) {
+	fns := a.validators
 	if a.Validator != nil {
-		val, err = a.Validator(val)
+		fns = append([]func(any, *Arguments) (any, error){func(val any, _ *Arguments) (any, error) {
+			return a.Validator(val)
+		}}, fns...)
+	}
+
+	var errs ArgErrors
+	for _, fn := range fns {
+		newVal, err := fn(val, a.owner)
+		if ve, ok := err.(*valueError); ok {
+			err = errorx.Rawf("argument '%s' %s", a.ShowName, ve.msg)
+		}
+
 		if err != nil {
-			return
+			if !a.collectErrs {
+				return nil, err
+			}
+			errs = append(errs, err)
+			continue
 		}
+		val = newVal
+	}
+
+	if len(errs) > 0 {
+		return nil, errs
 	}
+	return val, nil
+}
 
+// call the Handler and the handlers pipeline in order
+func (a *Argument) handle(val any) any {
+	if a.raw {
+		return val
+	}
 	if a.Handler != nil {
 		val = a.Handler(val)
 	}
+	for _, fn := range a.handlers {
+		val = fn(val)
+	}
+	return val
+}
 
-	a.Value.V = val
-	return
+// remove the duplicate elements of the slice, keep the first-seen order
+func uniqueSlice(val any) any {
+	rv := reflect.ValueOf(val)
+	if val == nil || rv.Kind() != reflect.Slice {
+		return val
+	}
+
+	seen := make(map[string]bool, rv.Len())
+	nrv := reflect.MakeSlice(rv.Type(), 0, rv.Len())
+	for i := 0; i < rv.Len(); i++ {
+		elem := rv.Index(i)
+		key := strutil.QuietString(elem.Interface())
+		if !seen[key] {
+			seen[key] = true
+			nrv = reflect.Append(nrv, elem)
+		}
+	}
+	return nrv.Interface()
+}
+
+// sort the slice in place, the numbers are compared numerically
+func sortSlice(val any) {
+	rv := reflect.ValueOf(val)
+	if val == nil || rv.Kind() != reflect.Slice {
+		return
+	}
+
+	sort.SliceStable(val, func(i, j int) bool {
+		x, y := rv.Index(i), rv.Index(j)
+		switch x.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return x.Int() < y.Int()
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			return x.Uint() < y.Uint()
+		case reflect.Float32, reflect.Float64:
+			return x.Float() < y.Float()
+		}
+		return strutil.QuietString(x.Interface()) < strutil.QuietString(y.Interface())
+	})
+}
+
+// split each token by comma in CSV-aware mode, and flatten the results.
+// a token with multi lines is read as multi records, they are flattened too.
+func splitCSVTokens(val any) ([]string, error) {
+	var tokens []string
+	switch typVal := val.(type) {
+	case string:
+		tokens = []string{typVal}
+	case []string:
+		tokens = typVal
+	default:
+		tokens = []string{strutil.QuietString(val)}
+	}
+
+	var ss []string
+	for _, tok := range tokens {
+		if tok == "" {
+			continue
+		}
+
+		r := csv.NewReader(strings.NewReader(tok))
+		r.FieldsPerRecord = -1
+		records, err := r.ReadAll()
+		if err != nil {
+			return nil, err
+		}
+		for _, fields := range records {
+			ss = append(ss, fields...)
+		}
+	}
+	return ss, nil
+}
+
+// intern the strings, equal values share one backing instance.
+// the intern map is scoped to the call.
+func internStrings(ss []string) []string {
+	pool := make(map[string]string, len(ss))
+	ns := make([]string, len(ss))
+	for i, s := range ss {
+		if v, ok := pool[s]; ok {
+			ns[i] = v
+		} else {
+			pool[s] = s
+			ns[i] = s
+		}
+	}
+	return ns
+}
+
+// parse the string(trim spaces) as an int, int64 or float64 number, returns the "expects ..." error on failed.
+// the argument can be nil on use it in the standalone validator.
+func parseNum[T int | int64 | float64](a *Argument, s string) (T, error) {
+	var num T
+	var err error
+	kind, str := "an integer", strings.TrimSpace(s)
+	switch ptr := any(&num).(type) {
+	case *int:
+		*ptr, err = strconv.Atoi(str)
+	case *int64:
+		*ptr, err = strconv.ParseInt(str, 10, 64)
+	case *float64:
+		kind = "a float"
+		*ptr, err = strconv.ParseFloat(str, 64)
+	}
+
+	if err == nil {
+		return num, nil
+	}
+	if a == nil {
+		return 0, errorx.Rawf("expects %s, got %q", kind, s)
+	}
+	return 0, errorx.Rawf("argument '%s' expects %s, got %q", a.ShowName, kind, s)
+}
+
+// convert the string value or each element of the strings value by fn.
+func convEach[T any](val any, fn func(s string) (T, error)) (any, error) {
+	switch typVal := val.(type) {
+	case []string:
+		ns := make([]T, len(typVal))
+		for i, s := range typVal {
+			nv, err := fn(s)
+			if err != nil {
+				return nil, err
+			}
+			ns[i] = nv
+		}
+		return ns, nil
+	case string:
+		return fn(typVal)
+	case nil:
+		return val, nil
+	}
+
+	// other typed slice. eg: []int after WithType("int")
+	if rv := reflect.ValueOf(val); rv.Kind() == reflect.Slice {
+		ns := make([]T, rv.Len())
+		for i := range ns {
+			nv, err := fn(strutil.QuietString(rv.Index(i).Interface()))
+			if err != nil {
+				return nil, err
+			}
+			ns[i] = nv
+		}
+		return ns, nil
+	}
+	return fn(strutil.QuietString(val))
+}
+
+// set the value to the reflect field, will convert value to the field type.
+func setFieldValue(fv reflect.Value, val any) error {
+	rv := reflect.ValueOf(val)
+	if rv.Type().AssignableTo(fv.Type()) {
+		fv.Set(rv)
+		return nil
+	}
+
+	switch fv.Kind() {
+	case reflect.String:
+		fv.SetString(strutil.QuietString(val))
+	case reflect.Bool:
+		bl, err := strutil.ToBool(strutil.QuietString(val))
+		if err != nil {
+			return err
+		}
+		fv.SetBool(bl)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i64, err := mathutil.ToInt64(val)
+		if err != nil {
+			return err
+		}
+		fv.SetInt(i64)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u64, err := mathutil.ToUint(val)
+		if err != nil {
+			return err
+		}
+		fv.SetUint(u64)
+	case reflect.Float32, reflect.Float64:
+		f64, err := mathutil.ToFloat(val)
+		if err != nil {
+			return err
+		}
+		fv.SetFloat(f64)
+	case reflect.Slice:
+		if rv.Kind() != reflect.Slice {
+			return errorx.Rawf("cannot convert %T to %s", val, fv.Type())
+		}
+
+		ns := reflect.MakeSlice(fv.Type(), rv.Len(), rv.Len())
+		for i := 0; i < rv.Len(); i++ {
+			if err := setFieldValue(ns.Index(i), rv.Index(i).Interface()); err != nil {
+				return err
+			}
+		}
+		fv.Set(ns)
+	default:
+		return errorx.Rawf("unsupported field type %s", fv.Type())
+	}
+	return nil
 }
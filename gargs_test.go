@@ -1,12 +1,31 @@
 package gcli_test
 
 import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"runtime"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
+	"unsafe"
 
 	"github.com/gookit/gcli/v3"
+	"github.com/gookit/goutil/arrutil"
+	"github.com/gookit/goutil/mathutil"
 	"github.com/gookit/goutil/testutil/assert"
+	"golang.org/x/text/unicode/norm"
 )
 
 func TestCommand_AddArg(t *testing.T) {
@@ -177,3 +196,2694 @@ func TestArgument_SetValue(t *testing.T) {
 	assert.NoErr(t, err)
 	assert.Eq(t, 12, arg.Val())
 }
+
+func TestArgument_WithIntern(t *testing.T) {
+	arg := gcli.NewArgument("names", "desc", false, true).WithIntern()
+
+	// build at runtime, the equal values have different backing storage
+	in := []string{strings.Repeat("ab", 4), "b", strings.Repeat("ab", 4), "b"}
+	assert.NotEq(t, strData(in[0]), strData(in[2]))
+
+	assert.NoErr(t, arg.SetValue(in))
+	ss := arg.Strings()
+	assert.Eq(t, []string{"abababab", "b", "abababab", "b"}, ss)
+	assert.Eq(t, strData(ss[0]), strData(ss[2]))
+}
+
+// get the pointer to the backing bytes of the string
+func strData(s string) uintptr {
+	return (*reflect.StringHeader)(unsafe.Pointer(&s)).Data
+}
+
+func TestArguments_CompleteArgs(t *testing.T) {
+	ags := gcli.Arguments{}
+	ags.AddArg("action", "desc").WithCompleter(func(prefix string, _ *gcli.Arguments) []string {
+		return []string{"start", "stop", "restart"}
+	})
+	ags.AddArg("names", "desc", false, true).WithCompleter(func(prefix string, _ *gcli.Arguments) []string {
+		return []string{"web", "worker"}
+	})
+
+	assert.Eq(t, []string{"start", "stop"}, ags.CompleteArgs(nil, "st"))
+	assert.Eq(t, []string{"restart"}, ags.CompleteArgs(nil, "re"))
+	assert.Eq(t, []string{"web", "worker"}, ags.CompleteArgs([]string{"start"}, "w"))
+	assert.Eq(t, []string{"worker"}, ags.CompleteArgs([]string{"start", "web"}, "wo"))
+	assert.Nil(t, ags.CompleteArgs([]string{"start"}, "x"))
+}
+
+func TestArgument_RequireConfirm(t *testing.T) {
+	ags := gcli.Arguments{}
+	ags.AddArg("env", "desc").RequireConfirm("Really delete?")
+
+	// skip on empty value
+	assert.NoErr(t, ags.ParseArgs([]string{}))
+
+	// stdin is not a terminal on testing
+	err := ags.ParseArgs([]string{"prod"})
+	assert.ErrMsg(t, err, "the argument 'env' requires confirmation, but stdin is not a terminal")
+
+	ags.SetAssumeYes(true)
+	assert.NoErr(t, ags.ParseArgs([]string{"prod"}))
+	assert.Eq(t, "prod", ags.Arg("env").String())
+
+	// every binding path requires confirmation
+	const notConfirmed = "the argument 'env' requires confirmation, but stdin is not a terminal"
+	newArgs := func() *gcli.Arguments {
+		ags := &gcli.Arguments{}
+		ags.AddArg("env", "desc").RequireConfirm("Really delete?")
+		return ags
+	}
+
+	ags2 := newArgs()
+	assert.ErrMsg(t, ags2.ParseNamedArgs([]string{"env=prod"}), notConfirmed)
+
+	ags2 = newArgs()
+	ags2.SetAllowInlineNames(true)
+	assert.ErrMsg(t, ags2.ParseArgs([]string{"env=prod"}), notConfirmed)
+
+	ags2 = newArgs()
+	assert.ErrMsg(t, ags2.BindArgs([]any{"prod"}), notConfirmed)
+
+	ags2 = newArgs()
+	ags2.Arg("env").WithDefault("prod")
+	assert.ErrMsg(t, ags2.ParseArgs(nil), notConfirmed)
+
+	t.Setenv("GCLI_TEST_CONFIRM_ENV", "prod")
+	ags2 = newArgs()
+	ags2.Arg("env").WithEnv("GCLI_TEST_CONFIRM_ENV")
+	assert.ErrMsg(t, ags2.ParseArgs(nil), notConfirmed)
+}
+
+func TestArguments_ParseLine(t *testing.T) {
+	ags := gcli.Arguments{}
+	ags.AddArg("name", "desc")
+	ags.AddArg("files", "desc", false, true)
+
+	assert.NoErr(t, ags.ParseLine(`tom "a b.txt" 'c d.txt' e\ f.txt`))
+	assert.Eq(t, "tom", ags.Arg("name").String())
+	assert.Eq(t, []string{"a b.txt", "c d.txt", "e f.txt"}, ags.Arg("files").Array())
+
+	assert.ErrMsg(t, ags.ParseLine(`tom "a b`), "unterminated quote \" in the input line")
+
+	ags.SetTokenizer(gcli.SimpleTokenizer)
+	assert.NoErr(t, ags.ParseReader(strings.NewReader("john  'x y'")))
+	assert.Eq(t, "john", ags.Arg("name").String())
+	assert.Eq(t, []string{"'x", "y'"}, ags.Arg("files").Array())
+}
+
+func TestArgument_AsBool(t *testing.T) {
+	arg := gcli.NewArgument("enable", "desc").AsBool()
+	arg.Init()
+
+	for _, s := range []string{"1", "yes", "ON", "True"} {
+		assert.NoErr(t, arg.SetValue(s))
+		assert.True(t, arg.Bool())
+	}
+	for _, s := range []string{"0", "No", "off", "FALSE"} {
+		assert.NoErr(t, arg.SetValue(s))
+		assert.False(t, arg.Bool())
+	}
+
+	err := arg.SetValue("abc")
+	assert.ErrMsg(t, err, `argument 'enable' expects a bool value(1/0, yes/no, on/off, true/false), got "abc"`)
+
+	arg = gcli.NewArgument("flags", "desc", false, true).AsBool()
+	arg.Init()
+	assert.NoErr(t, arg.SetValue([]string{"yes", "0", "on"}))
+	assert.Eq(t, []bool{true, false, true}, arg.Bools())
+	assert.Err(t, arg.SetValue([]string{"yes", "x"}))
+}
+
+func TestArguments_ExplainParse(t *testing.T) {
+	ags := gcli.Arguments{}
+	ags.AddArg("src", "desc", true).WithValidator(func(val any) (any, error) {
+		if val.(string) == "bad" {
+			return nil, errors.New("invalid src")
+		}
+		return val, nil
+	})
+	ags.AddArg("files", "desc", false, true)
+
+	assert.Eq(t, `'foo' → <src> (required, input, ok)
+'bar','baz' → [files...] (optional, 2 values, input, ok)`, ags.ExplainParse([]string{"foo", "bar", "baz"}))
+	// not change values
+	assert.False(t, ags.Arg("src").HasValue())
+
+	assert.Eq(t, `<src> (required, error: invalid src)
+[files...] (optional, not set)`, ags.ExplainParse([]string{"bad"}))
+	assert.Eq(t, `<src> (required, error: must set value for the argument: src(position#0))
+[files...] (optional, not set)`, ags.ExplainParse(nil))
+
+	// secret masked, default and terminator
+	ags = gcli.Arguments{}
+	ags.AddArg("password", "desc", true).WithSecret()
+	ags.AddArg("level", "desc").WithDefault("info")
+	ags.AddArg("rest", "desc", false, true)
+
+	expl := ags.ExplainParse([]string{"hunter2"})
+	assert.NotContains(t, expl, "hunter2")
+	assert.Eq(t, `'****' → <password> (required, input, ok)
+'info' → [level] (optional, default, ok)
+[rest...] (optional, not set)`, expl)
+	assert.Eq(t, `'****' → <password> (required, input, ok)
+'info' → [level] (optional, default, ok)
+'-','-x' → [rest...] (optional, 2 values, input, ok)`, ags.ExplainParse([]string{"s3", "--", "-", "-x"}))
+
+	// extra args
+	ags = gcli.Arguments{}
+	ags.AddArg("src", "desc")
+	assert.Eq(t, `'a' → [src] (optional, input, ok)
+'b' → (extra, ignored)`, ags.ExplainParse([]string{"a", "b"}))
+	ags.SetValidateNum(true)
+	assert.Eq(t, `'a' → [src] (optional, input, ok)
+error: entered too many arguments: [b]`, ags.ExplainParse([]string{"a", "b"}))
+}
+
+func TestArguments_AddRelation(t *testing.T) {
+	ags := gcli.Arguments{}
+	ags.AddArg("from", "desc", true)
+	ags.AddArg("to", "desc", true)
+
+	var calls []string
+	ags.AddRelation(func(ags *gcli.Arguments) error {
+		calls = append(calls, "diff")
+		if ags.Arg("from").String() == ags.Arg("to").String() {
+			return errors.New("the <to> cannot be same as <from>")
+		}
+		return nil
+	})
+	ags.AddRelation(func(ags *gcli.Arguments) error {
+		calls = append(calls, "second")
+		return nil
+	})
+
+	assert.ErrMsg(t, ags.ParseArgs([]string{"a", "a"}), "the <to> cannot be same as <from>")
+	assert.Eq(t, []string{"diff"}, calls)
+
+	calls = nil
+	assert.NoErr(t, ags.ParseArgs([]string{"a", "b"}))
+	assert.Eq(t, []string{"diff", "second"}, calls)
+}
+
+func TestArguments_ToArgv(t *testing.T) {
+	ags := gcli.Arguments{}
+	ags.AddArg("user", "desc", true)
+	ags.AddArg("password", "desc").WithSecret()
+	ags.AddArg("names", "desc", false, true)
+
+	assert.Empty(t, ags.ToArgv())
+	assert.NoErr(t, ags.ParseArgs([]string{"tom", "pwd", "a", "b"}))
+	assert.True(t, ags.Arg("password").IsSecret())
+	assert.Eq(t, []string{"tom", "****", "a", "b"}, ags.ToArgv())
+	assert.Eq(t, []string{"tom", "pwd", "a", "b"}, ags.ToArgv(true))
+	assert.Eq(t, "****", ags.Arg("password").String())
+	assert.Eq(t, "****", fmt.Sprint(ags.Arg("password")))
+	assert.Eq(t, "pwd", ags.Arg("password").GetValue())
+
+	ags.Arg("password").Set(nil)
+	ags.Arg("names").Set([]int{1, 2})
+	assert.Eq(t, []string{"tom"}, ags.ToArgv())
+
+	// stop at the first unbound argument
+	ags = gcli.Arguments{}
+	ags.AddArg("a", "desc")
+	ags.AddArg("b", "desc")
+	assert.NoErr(t, ags.ParseArgsMap(map[string]string{"b": "x"}, nil))
+	assert.Empty(t, ags.ToArgv())
+}
+
+func TestArgument_WithCountEqualTo(t *testing.T) {
+	ags := gcli.Arguments{}
+	ags.AddArg("n", "desc", true)
+	ags.AddArg("items", "desc", true, true).WithCountEqualTo("n")
+
+	assert.NoErr(t, ags.ParseArgs([]string{"2", "a", "b"}))
+
+	err := ags.ParseArgs([]string{"3", "a", "b"})
+	assert.ErrMsg(t, err, "argument 'items' requires 3 values(equals to argument 'n'), got 2")
+
+	err = ags.ParseArgs([]string{"x", "a"})
+	assert.ErrMsg(t, err, "argument 'n' value must be an integer for check count of argument 'items'")
+
+	assert.PanicsMsg(t, func() {
+		gcli.NewArgument("name", "desc").WithCountEqualTo("n")
+	}, "GCli: the argument 'name' must be arrayed for check values count")
+}
+
+func TestArgument_WithPrefixStrip(t *testing.T) {
+	arg := gcli.NewArgument("user", "desc").WithPrefixStrip("user:")
+	assert.NoErr(t, arg.SetValue("user:alice"))
+	assert.Eq(t, "alice", arg.String())
+	assert.NoErr(t, arg.SetValue("bob"))
+	assert.Eq(t, "bob", arg.String())
+	assert.NoErr(t, arg.SetValue("user:user:tom"))
+	assert.Eq(t, "user:tom", arg.String())
+
+	arg = gcli.NewArgument("users", "desc", false, true).WithPrefixStrip("user:")
+	assert.NoErr(t, arg.SetValue([]string{"user:alice", "bob"}))
+	assert.Eq(t, []string{"alice", "bob"}, arg.Strings())
+}
+
+func TestArgument_WithRetryPrompt(t *testing.T) {
+	ags := gcli.Arguments{}
+	ags.AddArg("num", "desc").WithValidator(str2int).WithRetryPrompt(3, "Please input a number: ")
+
+	// non-interactive on testing, will return error immediately
+	assert.Err(t, ags.ParseArgs([]string{"abc"}))
+	assert.NoErr(t, ags.ParseArgs([]string{"12"}))
+	assert.Eq(t, 12, ags.Arg("num").Int())
+}
+
+func TestArguments_ParseArgsMap(t *testing.T) {
+	newAgs := func() *gcli.Arguments {
+		ags := &gcli.Arguments{}
+		ags.AddArg("src", "desc", true)
+		ags.AddArg("dst", "desc", true)
+		ags.AddArg("opts", "desc", false, true)
+		return ags
+	}
+
+	ags := newAgs()
+	assert.NoErr(t, ags.ParseArgsMap(map[string]string{"dst": "b", "opts": "x,y"}, []string{"a"}))
+	assert.Eq(t, "a", ags.Arg("src").String())
+	assert.Eq(t, "b", ags.Arg("dst").String())
+	assert.Eq(t, []string{"x", "y"}, ags.Arg("opts").Array())
+
+	ags = newAgs()
+	assert.NoErr(t, ags.ParseArgsMap(nil, []string{"a", "b", "c", "d"}))
+	assert.Eq(t, []string{"c", "d"}, ags.Arg("opts").Array())
+
+	// the positional values bind by position
+	ags = newAgs()
+	assert.NoErr(t, ags.ParseArgsMap(map[string]string{"opts": "z"}, []string{"x", "y"}))
+	assert.Eq(t, "x", ags.Arg("src").String())
+	assert.Eq(t, "y", ags.Arg("dst").String())
+	assert.Eq(t, []string{"z"}, ags.Arg("opts").Array())
+	assert.Eq(t, "input", ags.Arg("dst").Source())
+	assert.Eq(t, "input", ags.Arg("opts").Source())
+
+	ags = newAgs()
+	assert.ErrMsg(t, ags.ParseArgsMap(map[string]string{"not": "v"}, nil), "unknown argument name 'not'")
+	assert.ErrMsg(t, ags.ParseArgsMap(map[string]string{"src": "a"}, []string{"a"}), "the argument 'src' is set by name, but also covered positionally")
+	assert.ErrMsg(t, newAgs().ParseArgsMap(map[string]string{"src": "x"}, []string{"y", "z"}), "the argument 'src' is set by name, but also covered positionally")
+	assert.ErrMsg(t, newAgs().ParseArgsMap(map[string]string{"src": "a"}, nil), "must set value for the argument: dst(position#1)")
+
+	// the post-parse rules are applied
+	ags = newAgs()
+	ags.AddRelation(func(ags *gcli.Arguments) error {
+		if ags.Arg("src").String() == ags.Arg("dst").String() {
+			return errors.New("the src and dst must be different")
+		}
+		return nil
+	})
+	var completed bool
+	ags.SetOnComplete(func(ags *gcli.Arguments) error {
+		completed = true
+		return nil
+	})
+	assert.ErrMsg(t, ags.ParseArgsMap(map[string]string{"dst": "a"}, []string{"a"}), "the src and dst must be different")
+	assert.False(t, completed)
+	assert.NoErr(t, ags.ParseArgsMap(map[string]string{"dst": "b"}, []string{"a"}))
+	assert.True(t, completed)
+
+	ags = newAgs()
+	ags.Arg("opts").WithArgCount(2, -1)
+	assert.ErrMsg(t, ags.ParseArgsMap(map[string]string{"opts": "x"}, []string{"a", "b"}), "argument 'opts' requires at least 2 values, got 1")
+
+	ags = newAgs()
+	ags.Arg("opts").RequireConfirm("Really?")
+	assert.ErrMsg(t, ags.ParseArgsMap(map[string]string{"opts": "x"}, []string{"a", "b"}), "the argument 'opts' requires confirmation, but stdin is not a terminal")
+}
+
+func TestArgument_WithUnicodeNorm(t *testing.T) {
+	// "é" as "e" + combining acute accent
+	nfd := "cafe\u0301"
+	nfc := "caf\u00e9"
+
+	arg := gcli.NewArgument("name", "desc").WithUnicodeNorm(norm.NFC)
+	assert.NoErr(t, arg.SetValue(nfd))
+	assert.Eq(t, nfc, arg.String())
+
+	arg = gcli.NewArgument("names", "desc", false, true).WithUnicodeNorm(norm.NFD)
+	assert.NoErr(t, arg.SetValue([]string{nfc, "abc"}))
+	assert.Eq(t, []string{nfd, "abc"}, arg.Strings())
+}
+
+func TestArguments_LintDefinition(t *testing.T) {
+	ags := gcli.Arguments{}
+	ags.AddArg("src", "desc", true)
+	ags.AddArg("dst", "desc", true)
+	assert.Empty(t, ags.LintDefinition())
+
+	ags.AddArg("opt0", "desc")
+	ags.AddArg("opt1", "")
+	ags.AddArg("names", "desc", false, true)
+	assert.Eq(t, []string{
+		"multiple optional arguments in a row: 'opt0', 'opt1', binding intent is unclear",
+		"optional argument 'opt1' before the arrayed argument 'names', binding intent is unclear",
+	}, ags.LintDefinition())
+}
+
+func TestArguments_BindToStruct(t *testing.T) {
+	ags := gcli.Arguments{}
+	ags.AddArg("name", "desc", true)
+	ags.AddArg("age", "desc")
+	ags.AddArg("ports", "desc", false, true)
+
+	type opts struct {
+		Name  string `pos:"name"`
+		Age   int    `pos:"age"`
+		Ports []int  `pos:"ports"`
+		Other string
+	}
+
+	st := &opts{Other: "keep"}
+	assert.NoErr(t, ags.ParseArgs([]string{"tom", "23", "80", "443"}))
+	assert.NoErr(t, ags.BindToStruct(st, "pos"))
+	assert.Eq(t, "tom", st.Name)
+	assert.Eq(t, 23, st.Age)
+	assert.Eq(t, []int{80, 443}, st.Ports)
+	assert.Eq(t, "keep", st.Other)
+
+	assert.ErrMsg(t, ags.BindToStruct(*st, "pos"), "must provide a pointer to struct for bind arguments")
+
+	assert.NoErr(t, ags.ParseArgs([]string{"tom", "abc"}))
+	assert.ErrMsg(t, ags.BindToStruct(st, "pos"), `cannot set argument 'age' value to field 'Age': strconv.ParseInt: parsing "abc": invalid syntax`)
+}
+
+func TestArguments_BindStruct(t *testing.T) {
+	type opts struct {
+		Host    string   `arg:"host,required" desc:"the host name"`
+		Port    int      `arg:"port" validate:"min=1,max=65535"`
+		Debug   bool     `arg:"debug"`
+		Ratio   float64  `arg:"ratio"`
+		Skip    string   `arg:"-"`
+		Files   []string `arg:"files,arrayed"`
+		private string
+	}
+
+	ags := gcli.Arguments{}
+	st := &opts{Skip: "keep"}
+	assert.NoErr(t, ags.BindStruct(st))
+	assert.Eq(t, "<host> [port] [debug] [ratio] [files...]", ags.Signature())
+	assert.Eq(t, "the host name", ags.Arg("host").Desc)
+	assert.True(t, ags.Arg("files").Arrayed)
+
+	assert.NoErr(t, ags.ParseArgs([]string{"localhost", "8080", "yes", "0.5", "a.txt", "b.txt"}))
+	assert.Eq(t, "localhost", st.Host)
+	assert.Eq(t, 8080, st.Port)
+	assert.True(t, st.Debug)
+	assert.Eq(t, 0.5, st.Ratio)
+	assert.Eq(t, []string{"a.txt", "b.txt"}, st.Files)
+	assert.Eq(t, "keep", st.Skip)
+
+	assert.ErrMsg(t, ags.ParseArgs([]string{"localhost", "0"}), "argument 'port' value must be >= 1, got 0")
+	assert.ErrMsg(t, ags.ParseArgs([]string{"localhost", "70000"}), "argument 'port' value must be <= 65535, got 70000")
+
+	// the typed slice field
+	type portsOpts struct {
+		Ports []int `arg:"ports,required" validate:"int,min=1"`
+	}
+	ps := &portsOpts{}
+	ags = gcli.Arguments{}
+	assert.NoErr(t, ags.BindStruct(ps))
+	assert.NoErr(t, ags.ParseArgs([]string{"1", "2"}))
+	assert.Eq(t, []int{1, 2}, ps.Ports)
+	assert.ErrMsg(t, ags.ParseArgs([]string{"1", "0"}), "argument 'ports' value must be >= 1, got 0")
+
+	ags = gcli.Arguments{}
+	assert.ErrMsg(t, ags.BindStruct(*st), "must provide a pointer to struct for bind arguments")
+	assert.ErrMsg(t, ags.BindStruct(&struct{ M map[string]string }{}), "unsupported type map[string]string of the field 'M' for bind argument")
+}
+
+func TestArguments_FillStruct(t *testing.T) {
+	ags := gcli.Arguments{}
+	ags.AddArg("src", "desc", true)
+	ags.AddArg("port", "desc")
+	ags.AddArg("tags", "desc", false, true)
+
+	type opts struct {
+		From  string `arg:"src"`
+		Port  int
+		Tags  []string `arg:"tags"`
+		Skip  string   `arg:"-"`
+		Other string
+	}
+
+	st := &opts{Skip: "keep", Other: "keep"}
+	assert.NoErr(t, ags.ParseArgs([]string{"./a", "80", "x", "y"}))
+	assert.NoErr(t, ags.FillStruct(st))
+	assert.Eq(t, "./a", st.From)
+	assert.Eq(t, 80, st.Port)
+	assert.Eq(t, []string{"x", "y"}, st.Tags)
+	assert.Eq(t, "keep", st.Skip)
+	assert.Eq(t, "keep", st.Other)
+
+	assert.ErrMsg(t, ags.FillStruct(*st), "must provide a pointer to struct for fill arguments")
+
+	assert.NoErr(t, ags.ParseArgs([]string{"./a", "abc"}))
+	assert.ErrMsg(t, ags.FillStruct(st), `cannot set argument 'port' value to field 'Port': strconv.ParseInt: parsing "abc": invalid syntax`)
+}
+
+func TestArgument_Complete(t *testing.T) {
+	ags := gcli.Arguments{}
+	ags.AddArg("remote", "desc").WithChoices([]string{"origin", "upstream"})
+	ags.AddArg("branch", "desc").WithCompleter(func(prefix string, ags *gcli.Arguments) []string {
+		if ags.Arg("remote").String() == "upstream" {
+			return []string{"main", "master"}
+		}
+		return []string{"main", "dev", "feat-x"}
+	})
+
+	assert.Eq(t, []string{"origin"}, ags.Arg("remote").Complete("o"))
+	assert.Eq(t, []string{"origin", "upstream"}, ags.Arg("remote").Complete(""))
+	assert.Nil(t, ags.Arg("remote").Complete("x"))
+
+	assert.NoErr(t, ags.ParseArgs([]string{"origin"}))
+	assert.Eq(t, []string{"main"}, ags.Arg("branch").Complete("ma"))
+	assert.NoErr(t, ags.ParseArgs([]string{"upstream"}))
+	assert.Eq(t, []string{"main", "master"}, ags.Arg("branch").Complete("ma"))
+}
+
+func TestArguments_EachArg(t *testing.T) {
+	ags := gcli.Arguments{}
+	ags.AddArg("src", "desc", true)
+	ags.AddArg("dst", "desc")
+	ags.AddArg("tags", "desc", false, true)
+
+	var names []string
+	ags.EachArg(func(i int, a *gcli.Argument) bool {
+		names = append(names, fmt.Sprint(i, ":", a.Name))
+		return true
+	})
+	assert.Eq(t, []string{"0:src", "1:dst", "2:tags"}, names)
+
+	names = names[:0]
+	ags.EachArg(func(i int, a *gcli.Argument) bool {
+		names = append(names, a.Name)
+		return a.Required
+	})
+	assert.Eq(t, []string{"src", "dst"}, names)
+
+	// Args() returns a copy
+	list := ags.Args()
+	list[0] = nil
+	assert.Eq(t, "src", ags.ArgByIndex(0).Name)
+	assert.Nil(t, (&gcli.Arguments{}).Args())
+}
+
+func TestArgument_WithRequiredIf(t *testing.T) {
+	ags := gcli.Arguments{}
+	ags.AddArg("mode", "desc")
+	ags.AddArg("target", "desc").WithRequiredIf(func(ags *gcli.Arguments) bool {
+		return ags.Arg("mode").String() == "deploy"
+	})
+
+	assert.NoErr(t, ags.ParseArgs(nil))
+	assert.NoErr(t, ags.ParseArgs([]string{"build"}))
+
+	err := ags.ParseArgs([]string{"deploy"})
+	assert.ErrMsg(t, err, "must set value for the argument: target(position#1)")
+	var pe *gcli.ArgParseError
+	assert.True(t, errors.As(err, &pe))
+	assert.Eq(t, gcli.ArgErrMissing, pe.Kind)
+
+	assert.NoErr(t, ags.ParseArgs([]string{"deploy", "prod"}))
+	assert.Eq(t, "prod", ags.Arg("target").GetValue())
+}
+
+func TestArgument_WithTrim(t *testing.T) {
+	ags := gcli.Arguments{}
+	ags.AddArg("name", "desc").WithTrim().WithLower()
+	ags.AddArg("code", "desc").WithUpper()
+	ags.AddArg("tags", "desc", false, true).WithTrim().WithUpper()
+
+	assert.NoErr(t, ags.ParseArgs([]string{"  Tom ", "cn", " a", "b "}))
+	assert.Eq(t, "tom", ags.Arg("name").GetValue())
+	assert.Eq(t, "CN", ags.Arg("code").GetValue())
+	assert.Eq(t, []string{"A", "B"}, ags.Arg("tags").GetValue())
+
+	// compose with the exists handler
+	ags = gcli.Arguments{}
+	ags.AddArg("name", "desc").WithFn(func(a *gcli.Argument) {
+		a.Handler = func(val any) any { return val.(string) + " " }
+	}).WithTrim()
+	assert.NoErr(t, ags.ParseArgs([]string{"inhere"}))
+	assert.Eq(t, "inhere", ags.Arg("name").GetValue())
+}
+
+func TestArgument_WithCountOnly(t *testing.T) {
+	ags := gcli.Arguments{}
+	ags.AddArg("verbose", "desc", false, true).WithCountOnly().WithArgCount(1, 3)
+
+	assert.NoErr(t, ags.ParseArgs([]string{"v", "v", "v"}))
+	assert.Eq(t, 3, ags.Arg("verbose").Int())
+	assert.Eq(t, 3, ags.Arg("verbose").GetValue())
+
+	assert.NoErr(t, ags.ParseArgs([]string{"v"}))
+	assert.Eq(t, 1, ags.Arg("verbose").Int())
+	assert.ErrMsg(t, ags.ParseArgs([]string{"v", "v", "v", "v"}), "argument 'verbose' accepts at most 3 values, got 4")
+
+	assert.PanicsMsg(t, func() {
+		gcli.NewArgument("level", "desc").WithCountOnly()
+	}, "GCli: the argument 'level' must be arrayed for count values")
+}
+
+func TestArguments_Schema(t *testing.T) {
+	ags := gcli.Arguments{}
+	ags.AddArg("action", "the action", true).WithChoices([]string{"start", "stop"})
+	ags.AddArg("token", "the token").WithSecret().WithDefault("abc")
+	ags.AddArg("level", "the level").WithDefault("info")
+
+	bs, err := ags.Schema()
+	assert.NoErr(t, err)
+	assert.Eq(t, `[{"name":"action","desc":"the action","show_name":"action","required":true,"arrayed":false,"choices":["start","stop"]},`+
+		`{"name":"token","desc":"the token","show_name":"token","required":false,"arrayed":false,"secret":true,"default":"****"},`+
+		`{"name":"level","desc":"the level","show_name":"level","required":false,"arrayed":false,"default":"info"}]`, string(bs))
+
+	ags2 := gcli.Arguments{}
+	assert.NoErr(t, ags2.LoadSchema(bs))
+	bs2, err := ags2.Schema()
+	assert.NoErr(t, err)
+	// the masked secret default is not loaded
+	assert.Eq(t, strings.Replace(string(bs), `,"default":"****"`, "", 1), string(bs2))
+	assert.True(t, ags2.Arg("token").IsSecret())
+	assert.Nil(t, ags2.Arg("token").Default())
+
+	assert.NoErr(t, ags2.ParseArgs([]string{"start"}))
+	assert.Eq(t, "info", ags2.Arg("level").GetValue())
+	assert.ErrMsg(t, ags2.ParseArgs([]string{"run"}), "argument 'action' must be one of: start, stop")
+
+	assert.ErrMsg(t, ags2.LoadSchema(bs), "the argument name 'action' already exists in command ''")
+	assert.ErrMsg(t, ags2.LoadSchema([]byte("{")), "invalid arguments schema: unexpected end of JSON input")
+
+	// invalid definitions return error, and nothing is added
+	ags3 := gcli.Arguments{}
+	assert.ErrMsg(t, ags3.LoadSchema([]byte(`[{"name":"opt"},{"name":"req","required":true}]`)),
+		"required argument 'req' cannot be defined after optional argument")
+	assert.Empty(t, ags3.Args())
+	assert.ErrMsg(t, ags3.LoadSchema([]byte(`[{"name":"ok"},{"name":"bad name"}]`)),
+		"the argument name 'bad name' is invalid, must match: ^[a-zA-Z][\\w-]*$")
+	assert.Empty(t, ags3.Args())
+	assert.ErrMsg(t, ags3.LoadSchema([]byte(`[{"name":"files","arrayed":true},{"name":"dst"}]`)),
+		"have defined an array argument, you cannot add argument 'dst'")
+	assert.Empty(t, ags3.Args())
+}
+
+func TestArguments_SetTerminator(t *testing.T) {
+	ags := gcli.Arguments{}
+	ags.AddArg("files", "desc", true, true).WithChoices([]string{"a", "b"})
+
+	args := []string{"a", "b", "--", "--weird", "-x"}
+	assert.NoErr(t, ags.ParseArgs(args))
+	assert.Eq(t, []string{"a", "b", "--weird", "-x"}, ags.Arg("files").GetValue())
+	assert.Eq(t, []string{"a", "b", "--", "--weird", "-x"}, args)
+
+	assert.NoErr(t, ags.ParseArgs([]string{"--", "-x"}))
+	assert.Eq(t, []string{"-x"}, ags.Arg("files").GetValue())
+	assert.Err(t, ags.ParseArgs([]string{"-x"}))
+
+	// custom terminator
+	ags.SetTerminator(":::")
+	assert.NoErr(t, ags.ParseArgs([]string{"a", ":::", "-x"}))
+	assert.Eq(t, []string{"a", "-x"}, ags.Arg("files").GetValue())
+
+	// disabled
+	ags.SetTerminator("")
+	assert.ErrMsg(t, ags.ParseArgs([]string{"a", "--", "-x"}), "argument 'files' must be one of: a, b")
+
+	// no arrayed argument
+	ags = gcli.Arguments{}
+	ags.AddArg("name", "desc", true)
+	ags.AddArg("mode", "desc")
+	assert.NoErr(t, ags.ParseArgs([]string{"tom", "--", "-x", "y"}))
+	assert.Eq(t, "tom", ags.Arg("name").GetValue())
+	assert.False(t, ags.Arg("mode").HasValue())
+	assert.Eq(t, []string{"-x", "y"}, ags.Remaining())
+	assert.ErrMsg(t, ags.ParseArgs([]string{"--", "x"}), "must set value for the argument: name(position#0)")
+
+	// count only
+	ags = gcli.Arguments{}
+	ags.AddArg("verbose", "desc", false, true).WithCountOnly()
+	assert.NoErr(t, ags.ParseArgs([]string{"v", "v", "--", "x"}))
+	assert.Eq(t, 3, ags.Arg("verbose").GetValue())
+
+	// inline names and separator
+	ags = gcli.Arguments{}
+	ags.SetAllowInlineNames(true)
+	ags.AddArg("src", "desc", true)
+	ags.AddArg("rest", "desc", false, true)
+	assert.NoErr(t, ags.ParseArgs([]string{"src=a", "--", "src=b", "-x"}))
+	assert.Eq(t, "a", ags.Arg("src").GetValue())
+	assert.Eq(t, []string{"src=b", "-x"}, ags.Arg("rest").GetValue())
+
+	ags = gcli.Arguments{}
+	ags.SetArgSeparator("::")
+	ags.AddArg("files", "desc", true, true)
+	ags.AddArg("rest", "desc", false, true)
+	assert.NoErr(t, ags.ParseArgs([]string{"a", "::", "b", "--", "::", "c"}))
+	assert.Eq(t, []string{"a"}, ags.Arg("files").GetValue())
+	assert.Eq(t, []string{"b", "::", "c"}, ags.Arg("rest").GetValue())
+}
+
+func TestArgument_WithDeprecated(t *testing.T) {
+	buf := new(bytes.Buffer)
+	ags := gcli.Arguments{}
+	ags.SetDeprecationOutput(buf)
+	ags.AddArg("name", "the name", true)
+	mode := ags.AddArg("mode", "the mode").WithDeprecated("use the option --mode instead")
+
+	assert.True(t, mode.IsDeprecated())
+	assert.False(t, ags.Arg("name").IsDeprecated())
+	assert.Eq(t, "use the option --mode instead", mode.DeprecatedMsg())
+	assert.Eq(t, "the mode (deprecated: use the option --mode instead)", mode.HelpDesc())
+
+	assert.NoErr(t, ags.ParseArgs([]string{"tom"}))
+	assert.Empty(t, buf.String())
+
+	assert.NoErr(t, ags.ParseArgs([]string{"tom", "fast"}))
+	assert.Eq(t, "fast", mode.GetValue())
+	assert.Eq(t, "WARNING: the argument 'mode' is deprecated, use the option --mode instead\n", buf.String())
+
+	// only warn once
+	assert.NoErr(t, ags.ParseArgs([]string{"tom", "slow"}))
+	assert.Eq(t, 1, strings.Count(buf.String(), "WARNING"))
+}
+
+func TestArguments_SetValidateMode(t *testing.T) {
+	ags := gcli.Arguments{}
+	ags.SetValidateNum(true)
+	ags.AddArg("port", "desc", true).WithType("int")
+	ags.AddArg("mode", "desc", true).WithChoices([]string{"fast", "slow"})
+	ags.AddArg("name", "desc", true)
+
+	var called bool
+	ags.SetOnComplete(func(ags *gcli.Arguments) error {
+		called = true
+		return nil
+	})
+
+	// default is fail-fast
+	assert.ErrMsg(t, ags.ParseArgs([]string{"abc", "run"}), `argument 'port' expects an integer, got "abc"`)
+
+	ags.SetValidateMode(gcli.ValidateAll)
+	err := ags.ParseArgs([]string{"abc", "run"})
+	assert.Err(t, err)
+	var errs gcli.ArgErrors
+	assert.True(t, errors.As(err, &errs))
+	assert.Len(t, errs, 3)
+	assert.Eq(t, `argument 'port' expects an integer, got "abc"`+"\n"+
+		"argument 'mode' must be one of: fast, slow\n"+
+		"must set value for the argument: name(position#2)", err.Error())
+	assert.False(t, called)
+
+	var pe *gcli.ArgParseError
+	assert.True(t, errors.As(err, &pe))
+	assert.Eq(t, gcli.ArgErrInvalid, pe.Kind)
+	assert.Eq(t, "port", pe.ArgName)
+
+	// not rely on the Unwrap() []error of go1.20+
+	pe = nil
+	assert.True(t, errs.As(&pe))
+	assert.Eq(t, "port", pe.ArgName)
+	assert.True(t, errs.Is(errs[1]))
+	assert.False(t, errs.Is(os.ErrNotExist))
+
+	err = ags.ParseArgs([]string{"80", "fast", "tom", "x", "y"})
+	assert.ErrMsg(t, err, "entered too many arguments: [x y]")
+	assert.False(t, called)
+
+	assert.NoErr(t, ags.ParseArgs([]string{"80", "fast", "tom"}))
+	assert.True(t, called)
+
+	// the group validator and relations are skipped after a bind error
+	var checked int
+	ags.SetGroupValidator(func(ags *gcli.Arguments) error {
+		checked++
+		return nil
+	})
+	ags.AddRelation(func(ags *gcli.Arguments) error {
+		checked++
+		return nil
+	})
+	assert.Err(t, ags.ParseArgs([]string{"abc", "fast", "tom"}))
+	assert.Eq(t, 0, checked)
+	assert.NoErr(t, ags.ParseArgs([]string{"80", "fast", "tom"}))
+	assert.Eq(t, 2, checked)
+}
+
+func TestValidateIP(t *testing.T) {
+	ags := gcli.Arguments{}
+	ags.AddArg("host", "desc").WithValidator(gcli.ValidateIP())
+	ags.AddArg("net", "desc").WithValidator(gcli.ValidateCIDR())
+	ags.AddArg("port", "desc").WithValidator(gcli.ValidatePort())
+	ags.AddArg("ratios", "desc", false, true).AddValidator(gcli.ValidatePercent())
+
+	assert.NoErr(t, ags.ParseArgs([]string{"127.0.0.1", "10.0.0.0/8", "8080", "50", "12.5%", "0"}))
+	assert.Eq(t, "127.0.0.1", ags.Arg("host").GetValue())
+	assert.Eq(t, 8080, ags.Arg("port").GetValue())
+	assert.Eq(t, []float64{50, 12.5, 0}, ags.Arg("ratios").GetValue())
+
+	assert.NoErr(t, ags.ParseArgs([]string{"::1", "fd00::/8", "0", "100"}))
+	assert.Eq(t, "::1", ags.Arg("host").GetValue())
+
+	assert.ErrMsg(t, ags.ParseArgs([]string{"127.0.0.256"}), `argument 'host' value "127.0.0.256" is not a valid IP address`)
+	assert.ErrMsg(t, ags.ParseArgs([]string{"::1", "10.0.0.0"}), `argument 'net' value "10.0.0.0" is not a valid CIDR notation`)
+	assert.ErrMsg(t, ags.ParseArgs([]string{"::1", "fd00::/8", "65536"}), `argument 'port' value "65536" is not a valid port, must be in 0-65535`)
+	assert.ErrMsg(t, ags.ParseArgs([]string{"::1", "fd00::/8", "80", "10", "101%"}), `argument 'ratios' value "101%" is not a valid percent, must be in 0-100`)
+
+	// direct call
+	_, err := gcli.ValidatePort()("abc")
+	assert.ErrMsg(t, err, `value "abc" is not a valid port, must be in 0-65535`)
+}
+
+func TestArgument_WithPredicate(t *testing.T) {
+	isLong := func(val any) bool {
+		return len(val.(string)) > 3
+	}
+
+	arg := gcli.NewArgument("name", "desc").WithPredicate(isLong, "the name is too short")
+	assert.NoErr(t, arg.SetValue("inhere"))
+	assert.Eq(t, "inhere", arg.String())
+	assert.ErrMsg(t, arg.SetValue("tom"), "the name is too short")
+
+	arg = gcli.NewArgument("names", "desc", false, true).WithPredicate(isLong, "the name is too short")
+	assert.NoErr(t, arg.SetValue([]string{"inhere", "john"}))
+	assert.ErrMsg(t, arg.SetValue([]string{"inhere", "tom"}), "the name is too short")
+
+	// typed elements
+	isEven := func(val any) bool {
+		return val.(int)%2 == 0
+	}
+	arg = gcli.NewArgument("nums", "desc", false, true).WithType("int").WithPredicate(isEven, "the number must be even")
+	arg.Init()
+	assert.NoErr(t, arg.SetValue([]string{"2", "4"}))
+	assert.Eq(t, []int{2, 4}, arg.Val())
+	assert.ErrMsg(t, arg.SetValue([]string{"2", "3"}), "the number must be even")
+
+	arg = gcli.NewArgument("flags", "desc", false, true).AsBool().WithPredicate(func(val any) bool {
+		return val.(bool)
+	}, "all flags must be on")
+	arg.Init()
+	assert.NoErr(t, arg.SetValue([]string{"yes", "on"}))
+	assert.ErrMsg(t, arg.SetValue([]string{"yes", "off"}), "all flags must be on")
+}
+
+func TestArguments_MarkdownTable(t *testing.T) {
+	ags := gcli.Arguments{}
+	ags.AddArg("src", "the source | path", true)
+	ags.AddArgByRule("token", "the api token;false;abc").WithSecret()
+	ags.AddArgByRule("level", "the log level;false;info")
+	ags.AddArg("debug", "hidden argument").WithHidden()
+	ags.AddArg("files", "the files", false, true)
+
+	assert.Eq(t, `| Name | Required | Arrayed | Default | Description |
+|------|----------|---------|---------|-------------|
+| src | yes | no |  | the source \| path |
+| token | no | no | **** | the api token |
+| level | no | no | info | the log level |
+| files... | no | yes |  | the files |
+`, ags.MarkdownTable())
+
+	// with choices column
+	ags.Arg("level").WithChoices([]string{"info", "debug"})
+	assert.Eq(t, `| Name | Required | Arrayed | Default | Choices | Description |
+|------|----------|---------|---------|---------|-------------|
+| src | yes | no |  |  | the source \| path |
+| token | no | no | **** |  | the api token |
+| level | no | no | info | info, debug | the log level |
+| files... | no | yes |  |  | the files |
+`, ags.MarkdownTable())
+}
+
+func TestArguments_ParseArgsAppend(t *testing.T) {
+	ags := gcli.Arguments{}
+	ags.AddArg("name", "desc", true)
+	ags.AddArg("tags", "desc", false, true)
+
+	assert.NoErr(t, ags.ParseArgs([]string{"tom", "a", "b"}))
+	assert.NoErr(t, ags.ParseArgsAppend([]string{"john", "c"}))
+	assert.Eq(t, "john", ags.Arg("name").String())
+	assert.Eq(t, []string{"a", "b", "c"}, ags.Arg("tags").Array())
+
+	assert.NoErr(t, ags.ParseArgsAppend([]string{"john"}))
+	assert.Eq(t, []string{"a", "b", "c"}, ags.Arg("tags").Array())
+
+	// replace on normal parse
+	assert.NoErr(t, ags.ParseArgs([]string{"tom", "d"}))
+	assert.Eq(t, []string{"d"}, ags.Arg("tags").Array())
+
+	// only fall back to the default when has no value yet
+	ags = gcli.Arguments{}
+	ags.AddArg("tags", "desc", false, true).WithDefault([]string{"def"})
+	assert.NoErr(t, ags.ParseArgsAppend([]string{"a", "b"}))
+	assert.NoErr(t, ags.ParseArgsAppend(nil))
+	assert.Eq(t, []string{"a", "b"}, ags.Arg("tags").Strings())
+
+	ags.Reset()
+	assert.NoErr(t, ags.ParseArgsAppend(nil))
+	assert.Eq(t, []string{"def"}, ags.Arg("tags").Strings())
+
+	// count, unique and sorted on the accumulated values
+	ags = gcli.Arguments{}
+	ags.AddArg("verbose", "desc", false, true).WithCountOnly().WithArgCount(0, 3)
+	assert.NoErr(t, ags.ParseArgsAppend([]string{"v", "v"}))
+	assert.NoErr(t, ags.ParseArgsAppend([]string{"v"}))
+	assert.Eq(t, 3, ags.Arg("verbose").Int())
+	assert.ErrMsg(t, ags.ParseArgsAppend([]string{"v"}), "argument 'verbose' accepts at most 3 values, got 4")
+
+	ags = gcli.Arguments{}
+	ags.AddArg("tags", "desc", false, true).WithUnique().WithSorted()
+	assert.NoErr(t, ags.ParseArgsAppend([]string{"b", "a"}))
+	assert.NoErr(t, ags.ParseArgsAppend([]string{"a", "c"}))
+	assert.Eq(t, []string{"a", "b", "c"}, ags.Arg("tags").Strings())
+
+	// on inline names
+	ags = gcli.Arguments{}
+	ags.SetAllowInlineNames(true)
+	ags.AddArg("tags", "desc", false, true).WithUnique()
+	assert.NoErr(t, ags.ParseArgsAppend([]string{"tags=a", "tags=b"}))
+	assert.NoErr(t, ags.ParseArgsAppend([]string{"tags=a", "tags=c"}))
+	assert.Eq(t, []string{"a", "b", "c"}, ags.Arg("tags").Strings())
+}
+
+func TestArgument_ValueSource(t *testing.T) {
+	ags := gcli.Arguments{}
+	ags.AddArg("name", "desc", true)
+	ags.AddArgByRule("level", "desc;false;info")
+	ags.AddArg("tags", "desc", false, true)
+
+	assert.Eq(t, gcli.ArgSourceNone, ags.Arg("name").ValueSource())
+	assert.NoErr(t, ags.ParseArgs([]string{"tom"}))
+	assert.Eq(t, map[string]gcli.ArgSource{
+		"name":  gcli.ArgSourceInput,
+		"level": gcli.ArgSourceDefault,
+		"tags":  gcli.ArgSourceNone,
+	}, ags.Sources())
+
+	assert.NoErr(t, ags.Arg("tags").SetValue([]string{"a"}))
+	assert.Eq(t, gcli.ArgSourceSet, ags.Arg("tags").ValueSource())
+}
+
+func TestArgument_WithMultipleOf(t *testing.T) {
+	arg := gcli.NewArgument("size", "desc").WithMultipleOf(512)
+	arg.Init()
+
+	assert.NoErr(t, arg.SetValue("1024"))
+	assert.Eq(t, 1024, arg.Int())
+	assert.ErrMsg(t, arg.SetValue("1000"), "argument 'size' value 1000 is not a multiple of 512")
+	assert.ErrMsg(t, arg.SetValue("abc"), `argument 'size' expects an integer, got "abc"`)
+
+	arg = gcli.NewArgument("sizes", "desc", false, true).WithMultipleOf(4)
+	arg.Init()
+	assert.NoErr(t, arg.SetValue([]string{"4", "8"}))
+	assert.Eq(t, []string{"4", "8"}, arg.Strings())
+	assert.ErrMsg(t, arg.SetValue([]string{"4", "6"}), "argument 'sizes' value 6 is not a multiple of 4")
+
+	// typed elements
+	arg = gcli.NewArgument("sizes", "desc", false, true).WithType("int").WithMultipleOf(2)
+	arg.Init()
+	assert.NoErr(t, arg.SetValue([]string{"2", "4"}))
+	assert.Eq(t, []int{2, 4}, arg.Val())
+	assert.ErrMsg(t, arg.SetValue([]string{"2", "5"}), "argument 'sizes' value 5 is not a multiple of 2")
+
+	assert.PanicsMsg(t, func() {
+		gcli.NewArgument("size", "desc").WithMultipleOf(0)
+	}, "GCli: the step for argument 'size' must be greater than 0")
+}
+
+func TestArguments_ParseArgsWithContext(t *testing.T) {
+	ags := gcli.Arguments{}
+	ags.AddArg("src", "desc", true)
+	ags.AddArg("build", "desc", true).WithContextDefault("build_id")
+	ags.AddArgByRule("env", "desc;false;dev").WithContextDefault("env")
+
+	ctx := map[string]any{"build_id": "b23"}
+	assert.NoErr(t, ags.ParseArgsWithContext(ctx, []string{"a"}))
+	assert.Eq(t, "b23", ags.Arg("build").String())
+	assert.Eq(t, gcli.ArgSourceContext, ags.Arg("build").ValueSource())
+	assert.Eq(t, "dev", ags.Arg("env").String())
+
+	ctx["env"] = "prod"
+	assert.NoErr(t, ags.ParseArgsWithContext(ctx, []string{"a", "b45"}))
+	assert.Eq(t, "b45", ags.Arg("build").String())
+	assert.Eq(t, "prod", ags.Arg("env").String())
+
+	err := ags.ParseArgsWithContext(nil, []string{"a"})
+	assert.ErrMsg(t, err, "must set value for the argument: build(position#1)")
+}
+
+func TestArgument_AsPairList(t *testing.T) {
+	arg := gcli.NewArgument("fields", "desc", true, true).AsPairList("=")
+	arg.Init()
+
+	assert.NoErr(t, arg.SetValue([]string{"name=string", "age=int", "name=text"}))
+	assert.Eq(t, []gcli.KV{
+		{Key: "name", Val: "string"},
+		{Key: "age", Val: "int"},
+		{Key: "name", Val: "text"},
+	}, arg.Pairs())
+
+	err := arg.SetValue([]string{"name=string", "age"})
+	assert.ErrMsg(t, err, `argument 'fields' element "age" must be in format KEY=VALUE`)
+}
+
+func TestArgument_SetPositionalOnly(t *testing.T) {
+	ags := gcli.Arguments{}
+	ags.AddArg("name", "desc", true)
+	ags.AddArg("pos1", "desc").SetPositionalOnly()
+
+	assert.NoErr(t, ags.ParseArgs([]string{"tom", "val1"}))
+	assert.Eq(t, "val1", ags.ArgByIndex(1).String())
+	assert.Eq(t, "<ARG1>", ags.ArgByIndex(1).HelpName())
+	assert.False(t, ags.HasArg("pos1"))
+	assert.PanicsMsg(t, func() {
+		ags.Arg("pos1")
+	}, "GCli: the argument 'pos1' is positional-only, please get it by index")
+
+	assert.ErrMsg(t, ags.ParseArgsMap(map[string]string{"pos1": "v"}, []string{"tom"}), "unknown argument name 'pos1'")
+}
+
+func TestArgument_WithDecode(t *testing.T) {
+	arg := gcli.NewArgument("payload", "desc").WithDecode(gcli.DecodeBase64)
+	arg.Init()
+	assert.NoErr(t, arg.SetValue(base64.StdEncoding.EncodeToString([]byte("hello"))))
+	assert.Eq(t, "hello", arg.String())
+	assert.ErrMsg(t, arg.SetValue("!!"), "argument 'payload' value is not valid base64: illegal base64 data at input byte 0")
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	_, _ = zw.Write([]byte("hello gzip"))
+	assert.NoErr(t, zw.Close())
+
+	arg = gcli.NewArgument("payload", "desc").WithDecode(gcli.DecodeBase64Gzip)
+	arg.Init()
+	assert.NoErr(t, arg.SetValue(base64.StdEncoding.EncodeToString(buf.Bytes())))
+	assert.Eq(t, "hello gzip", arg.String())
+
+	arg = gcli.NewArgument("payload", "desc").WithDecode(gcli.DecodeGzip)
+	arg.Init()
+	assert.NoErr(t, arg.SetValue(buf.String()))
+	assert.Eq(t, "hello gzip", arg.String())
+	assert.Err(t, arg.SetValue("not gzip"))
+}
+
+func TestArguments_SetOnComplete(t *testing.T) {
+	ags := gcli.Arguments{}
+	ags.AddArg("name", "desc", true)
+
+	var called int
+	ags.SetOnComplete(func(ags *gcli.Arguments) error {
+		called++
+		if ags.Arg("name").String() == "bad" {
+			return errors.New("bad name")
+		}
+		return nil
+	})
+
+	assert.Err(t, ags.ParseArgs(nil))
+	assert.Eq(t, 0, called)
+	assert.NoErr(t, ags.ParseArgs([]string{"tom"}))
+	assert.Eq(t, 1, called)
+	assert.ErrMsg(t, ags.ParseArgs([]string{"bad"}), "bad name")
+}
+
+func TestArguments_AddArgByRule_typed(t *testing.T) {
+	ags := gcli.Arguments{}
+
+	arg := ags.AddArgByRule("port", "int;required;desc=Port number;min=1;max=65535")
+	is := assert.New(t)
+	is.True(arg.Required)
+	is.Eq("Port number", arg.Desc)
+
+	is.NoErr(arg.SetValue("8080"))
+	is.Eq(8080, arg.Val())
+	is.ErrMsg(arg.SetValue("0"), "argument 'port' value must be >= 1, got 0")
+	is.ErrMsg(arg.SetValue("70000"), "argument 'port' value must be <= 65535, got 70000")
+	is.ErrMsg(arg.SetValue("abc"), `argument 'port' expects an integer, got "abc"`)
+
+	ags2 := gcli.Arguments{}
+	ags2.AddArgByRule("port", "int;required;desc=Port number;min=1;max=65535")
+	is.ErrMsg(ags2.ParseArgs([]string{"0"}), "argument 'port' value must be >= 1, got 0")
+	is.NoErr(ags2.ParseArgs([]string{"80"}))
+	is.Eq(80, ags2.Arg("port").Val())
+
+	arg = ags.AddArgByRule("ratio", "float;default=0.5;desc=the ratio")
+	is.Eq(0.5, arg.Val())
+	is.Eq(gcli.ArgSourceDefault, arg.ValueSource())
+
+	arg = ags.AddArgByRule("files", "type=[]string;desc=the files")
+	is.True(arg.Arrayed)
+
+	is.PanicsMsg(func() {
+		ags.AddArgByRule("name", "string;min=1")
+	}, "GCli: the rule key 'min' and 'max' only allow for type int and float, argument 'name'")
+	is.PanicsMsg(func() {
+		ags.AddArgByRule("name", "type=string;invalid")
+	}, "GCli: invalid rule item 'invalid' for argument 'name', must match `KEY=VAL` or 'required'")
+	is.PanicsMsg(func() {
+		ags.AddArgByRule("name", "string;size=1")
+	}, "GCli: invalid rule key 'size' for argument 'name'")
+	is.PanicsMsg(func() {
+		ags.AddArgByRule("name", "type=uint")
+	}, "GCli: invalid rule type 'uint' for argument 'name', allowed: int, float, bool, string, []string")
+
+	is.PanicsMsg(func() {
+		ags.AddArgByRule("name", "the name;required;desc=the name")
+	}, "GCli: invalid rule for argument 'name', the typed rule item 'desc=the name' must after a type")
+
+	// the type name without KEY=VAL items is the desc of the simple rule
+	ags = gcli.Arguments{}
+	arg = ags.AddArgByRule("name", "string;required")
+	is.Eq("string", arg.Desc)
+	is.True(arg.Required)
+}
+
+func TestArguments_DefinitionHash(t *testing.T) {
+	ags := gcli.Arguments{}
+	ags.AddArg("name", "desc", true)
+	ags.AddArg("tags", "desc", false, true)
+
+	hash := ags.DefinitionHash()
+	assert.Len(t, hash, 64)
+
+	// ignore runtime values
+	assert.NoErr(t, ags.ParseArgs([]string{"tom", "a"}))
+	assert.Eq(t, hash, ags.DefinitionHash())
+
+	ags.Arg("tags").Desc = "changed"
+	assert.NotEq(t, hash, ags.DefinitionHash())
+
+	// the fields affect the parse are hashed
+	changes := []func(ags *gcli.Arguments){
+		func(ags *gcli.Arguments) { ags.Arg("name").WithType("int") },
+		func(ags *gcli.Arguments) { ags.Arg("name").WithChoices([]string{"a", "b"}) },
+		func(ags *gcli.Arguments) { ags.Arg("name").WithAlias("user") },
+		func(ags *gcli.Arguments) { ags.Arg("tags").WithDefault([]string{"x"}) },
+		func(ags *gcli.Arguments) { ags.Arg("tags").WithEnv("GCLI_TAGS") },
+		func(ags *gcli.Arguments) { ags.Arg("tags").WithArgCount(1, 3) },
+		func(ags *gcli.Arguments) {
+			ags.Arg("name").AddValidator(func(val any) (any, error) { return val, nil })
+		},
+		func(ags *gcli.Arguments) { ags.SetValidateNum(true) },
+	}
+	for i, change := range changes {
+		ags = gcli.Arguments{}
+		ags.AddArg("name", "desc", true)
+		ags.AddArg("tags", "desc", false, true)
+		hash = ags.DefinitionHash()
+
+		change(&ags)
+		assert.NotEq(t, hash, ags.DefinitionHash(), "change #%d", i)
+	}
+}
+
+func TestArgument_WithAsyncValidator(t *testing.T) {
+	var calls int32
+	checkID := func(ctx context.Context, val any) (any, error) {
+		atomic.AddInt32(&calls, 1)
+		if ctx.Value("tenant") != "t1" {
+			return nil, errors.New("missing tenant")
+		}
+		if val.(string) == "bad" {
+			return nil, errors.New("not found")
+		}
+		return strings.ToUpper(val.(string)), nil
+	}
+
+	ags := gcli.Arguments{}
+	ags.SetContext(context.WithValue(context.Background(), "tenant", "t1"))
+	ags.AddArg("id", "desc", true).WithAsyncValidator(checkID, time.Minute)
+	ags.AddArg("refs", "desc", false, true).WithAsyncValidator(checkID, 0).WithAsyncLimit(2)
+
+	assert.NoErr(t, ags.ParseArgs([]string{"a1", "r1", "r2", "r3"}))
+	assert.Eq(t, "A1", ags.Arg("id").String())
+	assert.Eq(t, []any{"R1", "R2", "R3"}, ags.Arg("refs").Val())
+	assert.Eq(t, int32(4), atomic.LoadInt32(&calls))
+
+	// use cache
+	assert.NoErr(t, ags.ParseArgs([]string{"a1"}))
+	assert.Eq(t, int32(4), atomic.LoadInt32(&calls))
+
+	assert.ErrMsg(t, ags.ParseArgs([]string{"bad"}), `argument 'id' value "bad" is invalid: not found`)
+	assert.ErrMsg(t, ags.ParseArgs([]string{"a1", "r1", "bad"}), `argument 'refs' element #1 "bad" is invalid: not found`)
+
+	// each element of the typed slice
+	var seen []any
+	var mu sync.Mutex
+	ags = gcli.Arguments{}
+	ags.AddArg("nums", "desc", true, true).WithType("int").WithAsyncValidator(func(ctx context.Context, val any) (any, error) {
+		mu.Lock()
+		seen = append(seen, val)
+		mu.Unlock()
+		if val == "3" {
+			return nil, errors.New("not allowed")
+		}
+		return val, nil
+	}, time.Minute)
+	assert.NoErr(t, ags.ParseArgs([]string{"1", "2"}))
+	assert.Len(t, seen, 2)
+	assert.Contains(t, seen, "1")
+	assert.ErrMsg(t, ags.ParseArgs([]string{"1", "3"}), `argument 'nums' element #1 "3" is invalid: not allowed`)
+}
+
+func TestArguments_SetCaptureAfter(t *testing.T) {
+	ags := gcli.Arguments{}
+	ags.AddArg("name", "desc", true)
+	ags.AddArg("msg", "desc", false, true).WithPrefixStrip("-")
+	ags.SetCaptureAfter(":::", "msg")
+	ags.SetValidateNum(true)
+
+	assert.NoErr(t, ags.ParseArgs([]string{"tom", ":::", "-x", ":::", "b"}))
+	assert.Eq(t, "tom", ags.Arg("name").String())
+	assert.Eq(t, []string{"-x", ":::", "b"}, ags.Arg("msg").Array())
+
+	assert.ErrMsg(t, ags.ParseArgs([]string{"tom", "c", ":::", "b"}), "entered too many arguments: [c]")
+
+	// no sentinel
+	assert.NoErr(t, ags.ParseArgs([]string{"tom", "-c"}))
+	assert.Eq(t, []string{"c"}, ags.Arg("msg").Array())
+
+	ags.SetCaptureAfter(":::", "name")
+	assert.ErrMsg(t, ags.ParseArgs([]string{"tom"}), "the capture argument 'name' must be arrayed")
+}
+
+func TestArgument_WithCoerceFallback(t *testing.T) {
+	ags := gcli.Arguments{}
+	ags.AddArg("num", "desc", true).WithValidator(str2int).WithCoerceFallback(1)
+	ags.AddArg("size", "desc").WithValidator(str2int).WithCoerceFallback(10)
+
+	assert.NoErr(t, ags.ParseArgs([]string{"2", "abc"}))
+	assert.Eq(t, 2, ags.Arg("num").Int())
+	assert.Eq(t, 10, ags.Arg("size").Int())
+	assert.Eq(t, gcli.ArgSourceDefault, ags.Arg("size").ValueSource())
+
+	// required still returns error
+	assert.Err(t, ags.ParseArgs([]string{"abc", "20"}))
+
+	// the warning is printed, and fallback on all parse entries
+	buf := new(bytes.Buffer)
+	ags.SetDeprecationOutput(buf)
+	assert.NoErr(t, ags.ParseArgs([]string{"2", "abc"}))
+	assert.Contains(t, buf.String(), "WARNING: argument 'size' use the fallback value 10, the input is invalid: ")
+
+	ags.Arg("size").Set(nil)
+	assert.NoErr(t, ags.ParseArgsMap(map[string]string{"size": "abc"}, []string{"2"}))
+	assert.Eq(t, 10, ags.Arg("size").Int())
+
+	ags.Arg("size").Set(nil)
+	assert.NoErr(t, ags.ParseNamedArgs([]string{"size=abc", "2"}))
+	assert.Eq(t, 10, ags.Arg("size").Int())
+
+	ags.Arg("size").Set(nil)
+	assert.NoErr(t, ags.BindArgs([]any{"2", "abc"}))
+	assert.Eq(t, 10, ags.Arg("size").Int())
+	assert.Eq(t, gcli.ArgSourceDefault, ags.Arg("size").ValueSource())
+}
+
+func TestArguments_ParseArgsBytes(t *testing.T) {
+	ags := gcli.Arguments{}
+	ags.AddArg("name", "desc", true)
+	ags.AddArg("files", "desc", false, true)
+
+	assert.NoErr(t, ags.ParseArgsBytes([]byte("tom\r\na b.txt\n\nc.txt\n"), gcli.FramingNewline))
+	assert.Eq(t, "tom", ags.Arg("name").String())
+	assert.Eq(t, []string{"a b.txt", "", "c.txt"}, ags.Arg("files").Array())
+
+	assert.NoErr(t, ags.ParseArgsBytes([]byte("john\x00a\nb.txt\x00"), gcli.FramingNUL))
+	assert.Eq(t, "john", ags.Arg("name").String())
+	assert.Eq(t, []string{"a\nb.txt"}, ags.Arg("files").Array())
+
+	data := []byte{0, 0, 0, 3, 'b', 'o', 'b', 0, 0, 0, 0}
+	assert.NoErr(t, ags.ParseArgsBytes(data, gcli.FramingLengthPrefix))
+	assert.Eq(t, "bob", ags.Arg("name").String())
+	assert.Eq(t, []string{""}, ags.Arg("files").Array())
+
+	err := ags.ParseArgsBytes([]byte{0, 0, 0, 5, 'b'}, gcli.FramingLengthPrefix)
+	assert.ErrMsg(t, err, "invalid length-prefixed data: want 5 bytes, but only 1")
+	assert.ErrMsg(t, ags.ParseArgsBytes([]byte{0, 1}, gcli.FramingLengthPrefix), "invalid length-prefixed data: incomplete length prefix")
+}
+
+func TestArgument_AsWritablePath(t *testing.T) {
+	dir := t.TempDir()
+	arg := gcli.NewArgument("output", "desc").AsWritablePath()
+	arg.Init()
+
+	// can be created
+	assert.NoErr(t, arg.SetValue(dir+"/sub/../out.txt"))
+	assert.Eq(t, filepath.Join(dir, "out.txt"), arg.String())
+
+	// exists dir
+	assert.NoErr(t, arg.SetValue(dir))
+
+	err := arg.SetValue(dir + "/not-exist/out.txt")
+	assert.ErrMsg(t, err, fmt.Sprintf(`argument 'output' path %q is not writable: parent directory %q is missing`,
+		dir+"/not-exist/out.txt", filepath.Join(dir, "not-exist")))
+
+	arg = gcli.NewArgument("outputs", "desc", false, true).AsWritablePath()
+	arg.Init()
+	assert.NoErr(t, arg.SetValue([]string{dir + "/a.txt", dir + "/b.txt"}))
+	assert.Eq(t, []string{filepath.Join(dir, "a.txt"), filepath.Join(dir, "b.txt")}, arg.Strings())
+}
+
+func TestArgument_ChunksOf(t *testing.T) {
+	arg := gcli.NewArgument("kvs", "desc", false, true)
+	arg.Init()
+
+	chunks, err := arg.ChunksOf(2)
+	assert.NoErr(t, err)
+	assert.Empty(t, chunks)
+
+	assert.NoErr(t, arg.SetValue([]string{"k1", "v1", "k2", "v2"}))
+	chunks, err = arg.ChunksOf(2)
+	assert.NoErr(t, err)
+	assert.Eq(t, [][]string{{"k1", "v1"}, {"k2", "v2"}}, chunks)
+
+	_, err = arg.ChunksOf(3)
+	assert.ErrMsg(t, err, "argument 'kvs' values count 4 is not a multiple of 3")
+	_, err = arg.ChunksOf(0)
+	assert.ErrMsg(t, err, "the chunk size must be greater than 0, got 0")
+}
+
+func TestArgument_WithValidateRule(t *testing.T) {
+	is := assert.New(t)
+	arg := gcli.NewArgument("port", "desc").WithValidateRule("int,min=1,max=65535")
+	arg.Init()
+	is.NoErr(arg.SetValue("80"))
+	is.Eq(80, arg.Val())
+	is.ErrMsg(arg.SetValue("0"), "argument 'port' value must be >= 1, got 0")
+
+	arg = gcli.NewArgument("name", "desc").WithValidateRule("min=2,max=5,regex=^[a-z,]+$")
+	arg.Init()
+	is.NoErr(arg.SetValue("ab,c"))
+	is.ErrMsg(arg.SetValue("a"), "argument 'name' value length must be in range 2-5, got 1")
+	is.ErrMsg(arg.SetValue("AB"), `argument 'name' value "AB" must match: ^[a-z,]+$`)
+
+	arg = gcli.NewArgument("actions", "desc", false, true).WithValidateRule("choices=start|stop")
+	arg.Init()
+	is.NoErr(arg.SetValue([]string{"start", "stop"}))
+	is.ErrMsg(arg.SetValue([]string{"start", "run"}), "argument 'actions' must be one of: start, stop")
+
+	// typed elements
+	arg = gcli.NewArgument("ports", "desc", false, true).WithType("int").WithValidateRule("int,min=1")
+	arg.Init()
+	is.NoErr(arg.SetValue([]string{"1", "2"}))
+	is.Eq([]int{1, 2}, arg.Val())
+	is.ErrMsg(arg.SetValue([]string{"1", "0"}), "argument 'ports' value must be >= 1, got 0")
+
+	arg = gcli.NewArgument("file", "desc").WithValidateRule("file")
+	arg.Init()
+	is.NoErr(arg.SetValue("gargs.go"))
+	is.ErrMsg(arg.SetValue("not-exist.go"), `argument 'file' value "not-exist.go" is not an exists file`)
+
+	is.PanicsMsg(func() {
+		gcli.NewArgument("name", "desc").WithValidateRule("unknown")
+	}, "GCli: invalid validate rule item 'unknown' for argument 'name'")
+}
+
+func TestArguments_CloneWithValues(t *testing.T) {
+	ags := &gcli.Arguments{}
+	ags.AddArg("name", "desc", true)
+	ags.AddArg("tags", "desc", false, true)
+	assert.NoErr(t, ags.ParseArgs([]string{"tom", "a", "b"}))
+
+	nags := ags.CloneWithValues()
+	assert.Eq(t, "tom", nags.Arg("name").String())
+	assert.Eq(t, []string{"a", "b"}, nags.Arg("tags").Array())
+	assert.Eq(t, ags.DefinitionHash(), nags.DefinitionHash())
+
+	// change the clone values
+	nags.Arg("tags").Array()[0] = "changed"
+	assert.NoErr(t, nags.ParseArgs([]string{"john"}))
+	assert.Eq(t, "john", nags.Arg("name").String())
+
+	assert.Eq(t, "tom", ags.Arg("name").String())
+	assert.Eq(t, []string{"a", "b"}, ags.Arg("tags").Array())
+
+	vals := map[string][]int{"a": {1}}
+	nags.Arg("tags").Set(vals)
+	cags := nags.CloneWithValues()
+	vals["a"][0] = 2
+	assert.Eq(t, map[string][]int{"a": {1}}, cags.Arg("tags").Val())
+}
+
+func TestArgument_WithRewriteFile(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "rewrite.txt")
+	assert.NoErr(t, os.WriteFile(file, []byte("# legacy names\nold-api = api\n\nv1=v2\n"), 0644))
+
+	arg := gcli.NewArgument("names", "desc", false, true).WithRewriteFile(file)
+	assert.NoErr(t, arg.SetValue([]string{"old-api", "web", "v1"}))
+	assert.Eq(t, []string{"api", "web", "v2"}, arg.Strings())
+
+	assert.NoErr(t, os.WriteFile(file, []byte("invalid"), 0644))
+	assert.PanicsMsg(t, func() {
+		gcli.NewArgument("name", "desc").WithRewriteFile(file)
+	}, "GCli: load the rewrite file for argument 'name' error: invalid line #1 \"invalid\", must match `OLD=NEW`")
+}
+
+func TestArgument_WithSliceInvariant(t *testing.T) {
+	arg := gcli.NewArgument("files", "desc", true, true).WithSliceInvariant(gcli.SameDir())
+	arg.Init()
+	assert.NoErr(t, arg.SetValue([]string{"a/b.txt", "a/c.go"}))
+	assert.ErrMsg(t, arg.SetValue([]string{"a/b.txt", "d/c.txt"}),
+		`argument 'files' all elements must be in the same directory, but "a/b.txt" is "a" and "d/c.txt" is "d"`)
+
+	arg = gcli.NewArgument("files", "desc", true, true).WithSliceInvariant(gcli.SameExtension())
+	arg.Init()
+	assert.NoErr(t, arg.SetValue([]string{"a/b.txt", "d/c.txt"}))
+	assert.ErrMsg(t, arg.SetValue([]string{"a/b.txt", "a/c.go"}),
+		`argument 'files' all elements must have the same extension, but "a/b.txt" is ".txt" and "a/c.go" is ".go"`)
+
+	// the typed elements
+	var got []string
+	ags := gcli.Arguments{}
+	ags.AddArg("nums", "desc", true, true).WithType("int").WithSliceInvariant(func(ss []string) error {
+		got = ss
+		return errors.New("always fails")
+	})
+	assert.ErrMsg(t, ags.ParseArgs([]string{"1", "2"}), "argument 'nums' always fails")
+	assert.Eq(t, []string{"1", "2"}, got)
+}
+
+func TestArguments_SetAllowInlineNames(t *testing.T) {
+	ags := gcli.Arguments{}
+	ags.AddArg("src", "desc", true)
+	ags.AddArg("dst", "desc", true)
+	ags.AddArg("tags", "desc", false, true)
+	ags.SetAllowInlineNames(true)
+
+	assert.NoErr(t, ags.ParseArgs([]string{"dst=./b", "./a", "t1", "t2"}))
+	assert.Eq(t, "./a", ags.Arg("src").String())
+	assert.Eq(t, "./b", ags.Arg("dst").String())
+	assert.Eq(t, []string{"t1", "t2"}, ags.Arg("tags").Array())
+
+	assert.NoErr(t, ags.ParseArgs([]string{"tags=x", "./c", "tags=y", "./d"}))
+	assert.Eq(t, "./c", ags.Arg("src").String())
+	assert.Eq(t, "./d", ags.Arg("dst").String())
+	assert.Eq(t, []string{"x", "y"}, ags.Arg("tags").Array())
+
+	assert.ErrMsg(t, ags.ParseArgs([]string{"not=v"}), `unknown argument name 'not' in the token "not=v", valid names: src, dst, tags`)
+	assert.ErrMsg(t, ags.ParseArgs([]string{"src=a"}), "must set value for the argument: dst(position#1)")
+}
+
+func TestArgument_WithValueLogger(t *testing.T) {
+	var logs []string
+	arg := gcli.NewArgument("num", "desc").WithValidator(str2int).WithValueLogger(func(stage, detail string) {
+		logs = append(logs, stage+": "+detail)
+	})
+	arg.Handler = func(val any) any {
+		return val.(int) * 2
+	}
+
+	assert.NoErr(t, arg.SetValue("12"))
+	assert.Eq(t, []string{
+		"normalize: input value: 12",
+		"validate: validate value: 12",
+		"handle: handle value: 12",
+		"store: stored value: 24",
+	}, logs)
+
+	logs = nil
+	assert.Err(t, arg.SetValue("abc"))
+	assert.Len(t, logs, 3)
+	assert.Eq(t, `validate: error: strconv.Atoi: parsing "abc": invalid syntax`, logs[2])
+}
+
+func TestArgument_AsRegexp(t *testing.T) {
+	arg := gcli.NewArgument("pattern", "desc").AsRegexp()
+	arg.Init()
+	assert.Nil(t, arg.Regexp())
+
+	assert.NoErr(t, arg.SetValue("^ab+c$"))
+	assert.True(t, arg.Regexp().MatchString("abbc"))
+	assert.ErrMsg(t, arg.SetValue("a(b"), "argument 'pattern' has invalid regexp \"a(b\": error parsing regexp: missing closing ): `a(b`")
+
+	arg = gcli.NewArgument("patterns", "desc", false, true).AsRegexp()
+	arg.Init()
+	assert.NoErr(t, arg.SetValue([]string{"^a", "b$"}))
+	assert.Len(t, arg.Regexps(), 2)
+	assert.True(t, arg.Regexps()[1].MatchString("ab"))
+}
+
+func TestArguments_SetArrayGreedy(t *testing.T) {
+	ags := gcli.Arguments{}
+	ags.AddArg("name", "desc", true)
+	ags.AddArg("files", "desc", false, true)
+	ags.SetArrayGreedy(false)
+	ags.SetValidateNum(true)
+
+	// no required argument after the arrayed, same as greedy
+	assert.NoErr(t, ags.ParseArgs([]string{"tom", "a", "b"}))
+	assert.Eq(t, []string{"a", "b"}, ags.Arg("files").Array())
+}
+
+func TestArguments_BashCompletionSnippet(t *testing.T) {
+	ags := gcli.Arguments{}
+	ags.AddArg("action", "desc", true).WithCompleter(func(string, *gcli.Arguments) []string {
+		return []string{"start", "stop"}
+	})
+	ags.AddArg("name", "desc")
+	ags.AddArg("files", "desc", false, true)
+
+	assert.Eq(t, `# bash completion for the arguments of: myapp deploy
+_myapp_deploy_args() {
+    local cur="${COMP_WORDS[COMP_CWORD]}"
+    local pos=0 i
+    for ((i=2; i<COMP_CWORD; i++)); do
+        [[ "${COMP_WORDS[i]}" == -* ]] || ((pos++))
+    done
+    case $pos in
+        0) COMPREPLY=($(compgen -W 'start stop' -- "$cur")) ;;
+        1) COMPREPLY=() ;;
+        *) COMPREPLY=($(compgen -f -- "$cur")) ;;
+    esac
+}
+`, ags.BashCompletionSnippet("myapp deploy"))
+}
+
+func TestArguments_ValidateAgainst(t *testing.T) {
+	ags := gcli.Arguments{}
+	ags.AddArg("action", "desc", true)
+	ags.AddArg("port", "desc")
+	ags.AddArg("hosts", "desc", false, true)
+
+	min, max := 1.0, 65535.0
+	schema := gcli.ArgSchema{Args: []gcli.ArgSchemaItem{
+		{Name: "action", Required: true, Choices: []string{"start", "stop"}},
+		{Name: "port", Required: true, Type: "int", Min: &min, Max: &max},
+		{Name: "hosts", Pattern: `^[a-z.]+$`},
+	}}
+
+	assert.NoErr(t, ags.ParseArgs([]string{"start", "80", "a.com", "b.com"}))
+	assert.NoErr(t, ags.ValidateAgainst(schema))
+
+	assert.NoErr(t, ags.ParseArgs([]string{"run", "0", "a.com", "B1"}))
+	err := ags.ValidateAgainst(schema)
+	var errs gcli.ArgErrors
+	assert.True(t, errors.As(err, &errs))
+	assert.Len(t, errs, 3)
+	assert.Eq(t, `argument 'action' value "run" must be one of: start, stop
+argument 'port' value "0" is less than min 1
+argument 'hosts' value "B1" must match: ^[a-z.]+$`, err.Error())
+
+	schema.Args = append(schema.Args, gcli.ArgSchemaItem{Name: "other"})
+	assert.ErrMsg(t, ags.ValidateAgainst(schema), `argument 'action' value "run" must be one of: start, stop
+argument 'port' value "0" is less than min 1
+argument 'hosts' value "B1" must match: ^[a-z.]+$
+argument 'other' is not defined`)
+}
+
+func TestArgument_WithTildeExpand(t *testing.T) {
+	home, err := os.UserHomeDir()
+	assert.NoErr(t, err)
+
+	ags := gcli.Arguments{}
+	ags.AddArg("dir", "desc").WithTildeExpand().WithValidateRule("file")
+	ags.AddArg("paths", "desc", false, true).WithTildeExpand()
+
+	f, err := os.CreateTemp(home, "gcli-tilde-*")
+	if err != nil {
+		t.Skip("home dir is not writable:", err)
+	}
+	_ = f.Close()
+	defer os.Remove(f.Name())
+
+	name := filepath.Base(f.Name())
+	assert.NoErr(t, ags.ParseArgs([]string{"~/" + name, "~", "a/~/b", "~user/c"}))
+	assert.Eq(t, filepath.Join(home, name), ags.Arg("dir").String())
+	assert.Eq(t, []string{home, "a/~/b", "~user/c"}, ags.Arg("paths").Strings())
+}
+
+func TestArguments_CanParse(t *testing.T) {
+	ags := gcli.Arguments{}
+	ags.SetValidateNum(true)
+	ags.AddArg("src", "desc", true).WithValue("def")
+	ags.AddArg("num", "desc").WithValidator(func(val any) (any, error) {
+		return strconv.Atoi(val.(string))
+	})
+
+	var called bool
+	ags.SetOnComplete(func(ags *gcli.Arguments) error {
+		called = true
+		return nil
+	})
+
+	assert.True(t, ags.CanParse([]string{"a", "12"}))
+	assert.True(t, ags.CanParse([]string{"a"}))
+	assert.False(t, ags.CanParse([]string{}))
+	assert.False(t, ags.CanParse([]string{"a", "b"}))
+	assert.False(t, ags.CanParse([]string{"a", "12", "c"}))
+
+	// not mutated
+	assert.False(t, called)
+	assert.Eq(t, "def", ags.Arg("src").String())
+	assert.False(t, ags.Arg("num").HasValue())
+	assert.Eq(t, gcli.ArgSourceNone, ags.Arg("src").ValueSource())
+}
+
+func TestArguments_CanParse_noSideEffects(t *testing.T) {
+	r, w, err := os.Pipe()
+	assert.NoErr(t, err)
+	_, err = w.WriteString("hello")
+	assert.NoErr(t, err)
+	assert.NoErr(t, w.Close())
+
+	old := os.Stdin
+	os.Stdin = r
+	defer func() {
+		os.Stdin = old
+		_ = r.Close()
+	}()
+
+	buf := new(bytes.Buffer)
+	ags := gcli.Arguments{}
+	ags.SetDeprecationOutput(buf)
+	ags.AddArg("mode", "desc").WithDeprecated("will be removed")
+	ags.AddArg("content", "desc").WithStdin().RequireConfirm("Really?")
+
+	assert.True(t, ags.CanParse([]string{"fast", "-"}))
+	assert.Empty(t, buf.String())
+
+	// the stdin is kept for the real parse
+	ags.SetAssumeYes(true)
+	assert.NoErr(t, ags.ParseArgs([]string{"fast", "-"}))
+	assert.Eq(t, "hello", ags.Arg("content").GetValue())
+	assert.Eq(t, 1, strings.Count(buf.String(), "WARNING"))
+}
+
+func TestArgument_WithDisplayFunc(t *testing.T) {
+	ags := gcli.Arguments{}
+	ags.AddArg("timeout", "desc").WithValue(90 * time.Second).
+		WithValidator(func(val any) (any, error) {
+			return time.ParseDuration(val.(string))
+		}).
+		WithDisplayFunc(func(val any) string {
+			return fmt.Sprintf("%.1f min", val.(time.Duration).Minutes())
+		})
+
+	arg := ags.Arg("timeout")
+	assert.Eq(t, "1.5 min", arg.String())
+	assert.Contains(t, ags.MarkdownTable(), "| 1.5 min |")
+	assert.Eq(t, "'3m0s' → [timeout] (optional, input, ok: 3.0 min)", ags.ExplainParse([]string{"3m"}))
+
+	assert.NoErr(t, ags.ParseArgs([]string{"2m"}))
+	assert.Eq(t, "2.0 min", arg.String())
+	assert.Eq(t, 2*time.Minute, arg.GetValue())
+}
+
+func TestArgument_OnlyOnOS(t *testing.T) {
+	ags := gcli.Arguments{}
+	ags.AddArg("here", "desc").OnlyOnOS(runtime.GOOS, "none-os")
+	ags.AddArg("other", "desc").OnlyOnOS("none-os")
+
+	assert.True(t, ags.Arg("here").Visible())
+	assert.False(t, ags.Arg("other").Visible())
+	assert.NotContains(t, ags.MarkdownTable(), "| other |")
+
+	assert.NoErr(t, ags.ParseArgs([]string{"a"}))
+	assert.Eq(t, "a", ags.Arg("here").String())
+
+	err := ags.ParseArgs([]string{"a", "b"})
+	assert.ErrMsg(t, err, "the argument 'other' is not supported on "+runtime.GOOS+", only on: none-os")
+
+	// skip the default and env fallback on the unsupported OS
+	ags = gcli.Arguments{}
+	ags.AddArg("other", "desc").OnlyOnOS("none-os").WithDefault("x").WithEnv("GCLI_TEST_OS_ENV")
+	t.Setenv("GCLI_TEST_OS_ENV", "y")
+	assert.NoErr(t, ags.ParseArgs(nil))
+	assert.False(t, ags.Arg("other").HasValue())
+}
+
+func TestArgument_WithSplitCSV(t *testing.T) {
+	ags := gcli.Arguments{}
+	ags.AddArg("items", "desc", false, true).WithSplitCSV()
+
+	assert.NoErr(t, ags.ParseArgs([]string{`a,"b,c",d`, `"say ""hi""",x`, "e"}))
+	assert.Eq(t, []string{"a", "b,c", "d", `say "hi"`, "x", "e"}, ags.Arg("items").Strings())
+
+	// multi records in one token
+	ags.Reset()
+	assert.NoErr(t, ags.ParseArgs([]string{"a,b\nc,d", "e"}))
+	assert.Eq(t, []string{"a", "b", "c", "d", "e"}, ags.Arg("items").Strings())
+
+	err := ags.ParseArgs([]string{`a,"b`})
+	assert.Err(t, err)
+	assert.Contains(t, err.Error(), "argument 'items' has invalid CSV value")
+
+	assert.PanicsMsg(t, func() {
+		gcli.NewArgument("one", "desc").WithSplitCSV()
+	}, "GCli: the argument 'one' must be arrayed for split values")
+}
+
+func TestArgument_WithDefault(t *testing.T) {
+	ags := gcli.Arguments{}
+	ags.AddArg("name", "desc", true)
+	ags.AddArg("port", "the port").WithDefault("8080").WithValidator(func(val any) (any, error) {
+		return strconv.Atoi(val.(string))
+	})
+	ags.AddArg("tags", "the tags", false, true).WithDefault([]string{"a", "b"})
+
+	port := ags.Arg("port")
+	assert.False(t, port.HasValue())
+	assert.Eq(t, "8080", port.Default())
+	assert.Eq(t, "the port (default: 8080)", port.HelpDesc())
+	assert.Eq(t, "the tags (default: a,b)", ags.Arg("tags").HelpDesc())
+	assert.Eq(t, "desc", ags.Arg("name").HelpDesc())
+
+	assert.NoErr(t, ags.ParseArgs([]string{"inhere"}))
+	assert.Eq(t, 8080, port.Val())
+	assert.Eq(t, gcli.ArgSourceDefault, port.ValueSource())
+	assert.Eq(t, []string{"a", "b"}, ags.Arg("tags").Strings())
+
+	assert.NoErr(t, ags.ParseArgs([]string{"inhere", "90", "c"}))
+	assert.Eq(t, 90, port.Val())
+	assert.Eq(t, gcli.ArgSourceInput, port.ValueSource())
+
+	// invalid default
+	ags.Arg("tags").WithDefault(nil)
+	port.WithDefault("abc")
+	assert.Err(t, ags.ParseArgs([]string{"inhere"}))
+
+	assert.PanicsMsg(t, func() {
+		gcli.NewArgument("name", "desc", true).WithDefault("abc")
+	}, "GCli: the required argument 'name' cannot have a default value")
+}
+
+func TestArgument_AsFloatLocale(t *testing.T) {
+	ags := gcli.Arguments{}
+	ags.AddArg("price", "desc").AsFloatLocale(",")
+	ags.AddArg("nums", "desc", false, true).AsFloatLocale(".")
+
+	assert.NoErr(t, ags.ParseArgs([]string{"1.234,5", "1,000.25", "3"}))
+	assert.Eq(t, 1234.5, ags.Arg("price").Float())
+	assert.Eq(t, []float64{1000.25, 3}, ags.Arg("nums").Val())
+
+	err := ags.ParseArgs([]string{"1,5", "2", "x1"})
+	assert.ErrMsg(t, err, `argument 'nums' expects a float(decimal separator "."), got "x1"`)
+}
+
+func TestArguments_AddReserved(t *testing.T) {
+	ags := gcli.Arguments{}
+	ags.AddArg("src", "desc", true)
+	res := ags.AddReserved("mode")
+	ags.AddArg("dst", "desc").WithValidator(func(val any) (any, error) {
+		return strings.ToUpper(val.(string)), nil
+	})
+
+	assert.True(t, res.IsReserved())
+	assert.False(t, res.Required)
+	assert.Eq(t, "[reserved]", res.HelpName())
+	assert.Contains(t, ags.MarkdownTable(), "| [reserved] | no | no |  | reserved for future use |")
+	assert.Eq(t, "<src> [reserved] [dst]", ags.Signature())
+
+	assert.NoErr(t, ags.ParseArgs([]string{"a", "anything", "b"}))
+	assert.Eq(t, "anything", res.String())
+	assert.Eq(t, "B", ags.Arg("dst").String())
+
+	assert.NoErr(t, ags.ParseArgs([]string{"a"}))
+}
+
+func TestArgument_WithChoices(t *testing.T) {
+	ags := gcli.Arguments{}
+	ags.AddArg("action", "desc", true).WithChoices([]string{"start", "stop", "restart"})
+	ags.AddArg("envs", "desc", false, true).WithChoices([]string{"dev", "prod"}, true)
+
+	assert.Eq(t, []string{"start", "stop", "restart"}, ags.Arg("action").Choices())
+	assert.Eq(t, []string{"start", "stop"}, ags.CompleteArgs(nil, "st"))
+	assert.Contains(t, ags.BashCompletionSnippet("app"), "0) COMPREPLY=($(compgen -W 'start stop restart' -- \"$cur\")) ;;")
+
+	assert.NoErr(t, ags.ParseArgs([]string{"stop", "DEV", "prod"}))
+	assert.Eq(t, []string{"DEV", "prod"}, ags.Arg("envs").Strings())
+
+	err := ags.ParseArgs([]string{"Stop"})
+	assert.ErrMsg(t, err, "argument 'action' must be one of: start, stop, restart")
+
+	err = ags.ParseArgs([]string{"start", "dev", "test"})
+	assert.ErrMsg(t, err, "argument 'envs' must be one of: dev, prod")
+}
+
+func TestArgument_WithValidator_keepBuiltin(t *testing.T) {
+	var called int
+	ags := gcli.Arguments{}
+	ags.AddArg("action", "desc").WithChoices([]string{"a", "b"}).WithValidator(func(val any) (any, error) {
+		called++
+		return val, nil
+	})
+	ags.AddArg("num", "desc").WithType("int").WithValidator(func(val any) (any, error) {
+		return val, nil
+	})
+
+	assert.NoErr(t, ags.ParseArgs([]string{"a", "12"}))
+	assert.Eq(t, 1, called)
+	assert.Eq(t, 12, ags.Arg("num").GetValue())
+	assert.ErrMsg(t, ags.ParseArgs([]string{"bogus"}), "argument 'action' must be one of: a, b")
+	assert.ErrMsg(t, ags.ParseArgs([]string{"b", "x"}), `argument 'num' expects an integer, got "x"`)
+}
+
+func TestArguments_ParseArgsRemain(t *testing.T) {
+	ags := gcli.Arguments{}
+	ags.AddArg("src", "desc", true)
+	ags.AddArg("dst", "desc")
+
+	remain, err := ags.ParseArgsRemain([]string{"a", "b", "-v", "c"})
+	assert.NoErr(t, err)
+	assert.Eq(t, []string{"-v", "c"}, remain)
+	assert.Eq(t, remain, ags.Remaining())
+
+	remain, err = ags.ParseArgsRemain([]string{"a"})
+	assert.NoErr(t, err)
+	assert.Empty(t, remain)
+
+	// too many
+	ags.SetValidateNum(true)
+	remain, err = ags.ParseArgsRemain([]string{"a", "b", "c"})
+	assert.Err(t, err)
+	assert.Eq(t, []string{"c"}, remain)
+
+	// arrayed consume all
+	ags = gcli.Arguments{}
+	ags.AddArg("src", "desc", true)
+	ags.AddArg("files", "desc", false, true)
+	remain, err = ags.ParseArgsRemain([]string{"a", "b", "c"})
+	assert.NoErr(t, err)
+	assert.Empty(t, remain)
+}
+
+func TestArgument_WithBlocklist(t *testing.T) {
+	ags := gcli.Arguments{}
+	ags.AddArg("user", "desc").WithBlocklist("root", "admin")
+	ags.AddArg("names", "desc", false, true).WithBlocklistFold("con", "nul")
+
+	assert.NoErr(t, ags.ParseArgs([]string{"Root", "a", "b"}))
+	assert.ErrMsg(t, ags.ParseArgs([]string{"admin"}), `argument 'user' value "admin" is not allowed`)
+	assert.ErrMsg(t, ags.ParseArgs([]string{"inhere", "a", "NUL"}), `argument 'names' value "NUL" is not allowed`)
+}
+
+func TestArguments_ParseResultJSON(t *testing.T) {
+	ags := gcli.Arguments{}
+	ags.AddArg("src", "desc", true)
+	ags.AddArg("token", "desc").WithSecret()
+	ags.AddArg("level", "desc").WithDefault("info")
+	ags.AddArg("files", "desc", false, true)
+
+	assert.NoErr(t, ags.ParseArgs([]string{"a", "abc"}))
+	bs, err := ags.ParseResultJSON()
+	assert.NoErr(t, err)
+	assert.Eq(t, `[{"name":"src","value":"a","source":"input","defaulted":false},`+
+		`{"name":"token","value":"****","source":"input","defaulted":false},`+
+		`{"name":"level","value":"info","source":"default","defaulted":true},`+
+		`{"name":"files","value":null,"source":"","defaulted":false}]`, string(bs))
+
+	assert.NoErr(t, ags.ParseArgs([]string{"a", "abc", "debug", "f1", "f2"}))
+	bs, err = ags.ParseResultJSON()
+	assert.NoErr(t, err)
+	assert.Contains(t, string(bs), `{"name":"files","value":["f1","f2"],"source":"input","defaulted":false}`)
+
+	// secret arrayed value
+	ags.Arg("files").WithSecret()
+	bs, err = ags.ParseResultJSON()
+	assert.NoErr(t, err)
+	assert.Contains(t, string(bs), `{"name":"files","value":["****","****"],"source":"input","defaulted":false}`)
+}
+
+func TestArgument_IntE(t *testing.T) {
+	ags := gcli.Arguments{}
+	port := ags.AddArg("port", "desc")
+	ratio := ags.AddArg("ratio", "desc")
+
+	iv, err := port.IntE()
+	assert.NoErr(t, err)
+	assert.Eq(t, 0, iv)
+
+	assert.NoErr(t, ags.ParseArgs([]string{"abc", "0.5"}))
+	_, err = port.IntE()
+	assert.ErrMsg(t, err, `argument 'port' expects an integer, got "abc"`)
+	_, err = port.Int64E()
+	assert.ErrMsg(t, err, `argument 'port' expects an integer, got "abc"`)
+	_, err = port.FloatE()
+	assert.ErrMsg(t, err, `argument 'port' expects a float, got "abc"`)
+
+	fv, err := ratio.FloatE()
+	assert.NoErr(t, err)
+	assert.Eq(t, 0.5, fv)
+
+	// with handler
+	port.Handler = func(val any) any {
+		return strings.TrimPrefix(val.(string), "abc")
+	}
+	assert.NoErr(t, ags.ParseArgs([]string{"abc8080"}))
+	i64, err := port.Int64E()
+	assert.NoErr(t, err)
+	assert.Eq(t, int64(8080), i64)
+}
+
+func TestArguments_SetArgSeparator(t *testing.T) {
+	ags := gcli.Arguments{}
+	ags.SetArgSeparator("--")
+	files := ags.AddArg("files", "desc", true, true)
+	mode := ags.AddArg("mode", "desc", true)
+	opts := ags.AddArg("opts", "desc", false, true)
+
+	assert.NoErr(t, ags.ParseArgs([]string{"a.txt", "b.txt", "--", "fast", "-v", "-x"}))
+	assert.Eq(t, []string{"a.txt", "b.txt"}, files.Strings())
+	assert.Eq(t, "fast", mode.String())
+	assert.Eq(t, []string{"-v", "-x"}, opts.Strings())
+
+	// required arg in the missing segment
+	err := ags.ParseArgs([]string{"a.txt"})
+	assert.ErrMsg(t, err, "must set value for the argument: mode(position#1)")
+
+	assert.NoErr(t, ags.ParseArgs([]string{"a.txt", "--", "slow"}))
+	assert.Eq(t, "slow", mode.String())
+
+	// extra segments
+	remain, err := ags.ParseArgsRemain([]string{"a", "--", "m", "--", "x"})
+	assert.NoErr(t, err)
+	assert.Eq(t, []string{"x"}, remain)
+	ags.SetValidateNum(true)
+	err = ags.ParseArgs([]string{"a", "--", "m", "--", "x"})
+	assert.ErrMsg(t, err, "entered too many argument groups separated by '--'")
+
+	// without separator
+	assert.PanicsMsg(t, func() {
+		ags := gcli.Arguments{}
+		ags.AddArg("files", "desc", true, true)
+		ags.AddArg("opts", "desc", false, true)
+	}, "GCli: have defined an array argument, you cannot add argument 'opts'")
+}
+
+func TestArgument_WithEnv(t *testing.T) {
+	t.Setenv("GCLI_TEST_TOKEN", "env-token")
+	t.Setenv("GCLI_TEST_LEVEL", "warn")
+	t.Setenv("GCLI_TEST_PATHS", "a,b")
+
+	ags := gcli.Arguments{}
+	token := ags.AddArg("token", "desc", true).WithEnv("GCLI_TEST_TOKEN")
+	level := ags.AddArg("level", "desc").WithEnv("GCLI_TEST_LEVEL").WithDefault("info")
+	ags.AddArg("paths", "desc", false, true).WithEnv("GCLI_TEST_PATHS", ",")
+
+	assert.NoErr(t, ags.ParseArgs(nil))
+	assert.Eq(t, "env-token", token.String())
+	assert.Eq(t, gcli.ArgSourceEnv, token.ValueSource())
+	assert.Eq(t, "warn", level.String())
+	assert.Eq(t, []string{"a", "b"}, ags.Arg("paths").Strings())
+
+	assert.NoErr(t, ags.ParseArgs([]string{"cli-token", "debug"}))
+	assert.Eq(t, "cli-token", token.String())
+	assert.Eq(t, gcli.ArgSourceInput, token.ValueSource())
+	assert.Eq(t, "debug", level.String())
+
+	// env > default
+	os.Unsetenv("GCLI_TEST_LEVEL")
+	assert.NoErr(t, ags.ParseArgs([]string{"cli-token"}))
+	assert.Eq(t, "info", level.String())
+	assert.Eq(t, gcli.ArgSourceDefault, level.ValueSource())
+
+	os.Unsetenv("GCLI_TEST_TOKEN")
+	assert.ErrMsg(t, ags.ParseArgs(nil), "must set value for the argument: token(position#0)")
+}
+
+func TestArgument_WithStdin(t *testing.T) {
+	mockStdin := func(t *testing.T, input string) {
+		r, w, err := os.Pipe()
+		assert.NoErr(t, err)
+		_, err = w.WriteString(input)
+		assert.NoErr(t, err)
+		assert.NoErr(t, w.Close())
+
+		old := os.Stdin
+		os.Stdin = r
+		t.Cleanup(func() {
+			os.Stdin = old
+			_ = r.Close()
+		})
+	}
+
+	ags := gcli.Arguments{}
+	content := ags.AddArg("content", "desc").WithStdin().WithValidator(func(val any) (any, error) {
+		return strings.ToUpper(val.(string)), nil
+	})
+	lines := ags.AddArg("lines", "desc", false, true).WithStdin()
+
+	mockStdin(t, "hello\nworld\n")
+	assert.NoErr(t, ags.ParseArgs([]string{"-"}))
+	assert.Eq(t, "HELLO\nWORLD", content.String())
+	assert.Eq(t, gcli.ArgSourceStdin, content.ValueSource())
+
+	mockStdin(t, "a\r\nb\n")
+	assert.NoErr(t, ags.ParseArgs([]string{"x", "-"}))
+	assert.Eq(t, []string{"a", "b"}, lines.Strings())
+
+	// "-" in multi values is not read
+	assert.NoErr(t, ags.ParseArgs([]string{"x", "-", "c"}))
+	assert.Eq(t, []string{"-", "c"}, lines.Strings())
+
+	// read twice
+	mockStdin(t, "data")
+	err := ags.ParseArgs([]string{"-", "-"})
+	assert.ErrMsg(t, err, "argument 'lines' cannot read from stdin, it has been read by argument 'content'")
+
+	// on the named and typed entries
+	mockStdin(t, "from map")
+	assert.NoErr(t, ags.ParseArgsMap(map[string]string{"content": "-"}, nil))
+	assert.Eq(t, "FROM MAP", content.String())
+	assert.Eq(t, gcli.ArgSourceStdin, content.ValueSource())
+
+	mockStdin(t, "c\nd")
+	assert.NoErr(t, ags.ParseNamedArgs([]string{"lines=-", "x"}))
+	assert.Eq(t, []string{"c", "d"}, lines.Strings())
+
+	mockStdin(t, "typed")
+	assert.NoErr(t, ags.BindArgs([]any{"-"}))
+	assert.Eq(t, "TYPED", content.String())
+}
+
+func TestArgument_WithArgCount(t *testing.T) {
+	ags := gcli.Arguments{}
+	ags.AddArg("hosts", "desc", false, true).WithArgCount(2, 5)
+
+	assert.NoErr(t, ags.ParseArgs([]string{"a", "b"}))
+	assert.NoErr(t, ags.ParseArgs([]string{"a", "b", "c", "d", "e"}))
+	assert.ErrMsg(t, ags.ParseArgs([]string{"a"}), "argument 'hosts' requires at least 2 values, got 1")
+	assert.ErrMsg(t, ags.ParseArgs(strings.Split("a b c d e f g", " ")), "argument 'hosts' accepts at most 5 values, got 7")
+	// optional but min > 0, no values is also checked
+	ags.Reset()
+	assert.ErrMsg(t, ags.ParseArgs([]string{}), "argument 'hosts' requires at least 2 values, got 0")
+
+	ags = gcli.Arguments{}
+	ags.AddArg("hosts", "desc", false, true).WithArgCount(1, -1)
+	assert.NoErr(t, ags.ParseArgs(strings.Split("a b c d e f g", " ")))
+
+	assert.PanicsMsg(t, func() {
+		gcli.NewArgument("host", "desc").WithArgCount(1, 2)
+	}, "GCli: the argument 'host' must be arrayed for limit values count")
+	assert.Panics(t, func() {
+		gcli.NewArgument("hosts", "desc", false, true).WithArgCount(3, 2)
+	})
+}
+
+func TestArgParseError(t *testing.T) {
+	ags := gcli.Arguments{}
+	ags.SetValidateNum(true)
+	ags.AddArg("name", "desc", true)
+	ags.AddArg("age", "desc").WithValidator(func(val any) (any, error) {
+		return strconv.Atoi(val.(string))
+	})
+
+	var pe *gcli.ArgParseError
+	err := ags.ParseArgs(nil)
+	assert.True(t, errors.As(err, &pe))
+	assert.Eq(t, gcli.ArgErrMissing, pe.Kind)
+	assert.Eq(t, "name", pe.ArgName)
+	assert.Eq(t, 0, pe.Position)
+	assert.Eq(t, "must set value for the argument: name(position#0)", err.Error())
+
+	err = ags.ParseArgs([]string{"inhere", "abc"})
+	assert.True(t, errors.As(err, &pe))
+	assert.Eq(t, gcli.ArgErrInvalid, pe.Kind)
+	assert.Eq(t, "age", pe.ArgName)
+	assert.Eq(t, 1, pe.Position)
+	var numErr *strconv.NumError
+	assert.True(t, errors.As(err, &numErr))
+
+	err = ags.ParseArgs([]string{"inhere", "20", "a", "b"})
+	assert.True(t, errors.As(err, &pe))
+	assert.Eq(t, gcli.ArgErrTooMany, pe.Kind)
+	assert.Eq(t, "", pe.ArgName)
+	assert.Eq(t, 2, pe.Position)
+	assert.Eq(t, "entered too many arguments: [a b]", err.Error())
+
+	// invalid value via named args
+	err = ags.ParseNamedArgs([]string{"name=inhere", "age=abc"})
+	assert.True(t, errors.As(err, &pe))
+	assert.Eq(t, gcli.ArgErrInvalid, pe.Kind)
+	assert.Eq(t, "age", pe.ArgName)
+
+	// invalid default and env values
+	ags = gcli.Arguments{}
+	ags.AddArg("port", "desc").WithType("int").WithDefault("abc")
+	err = ags.ParseArgs(nil)
+	assert.True(t, errors.As(err, &pe))
+	assert.Eq(t, gcli.ArgErrInvalid, pe.Kind)
+	assert.Eq(t, "port", pe.ArgName)
+
+	t.Setenv("GCLI_TEST_PORT", "xyz")
+	ags = gcli.Arguments{}
+	ags.AddArg("port", "desc").WithType("int").WithEnv("GCLI_TEST_PORT")
+	err = ags.ParseArgs(nil)
+	assert.True(t, errors.As(err, &pe))
+	assert.Eq(t, gcli.ArgErrInvalid, pe.Kind)
+	assert.ErrMsg(t, err, `argument 'port' expects an integer, got "xyz"`)
+
+	// the values count range
+	ags = gcli.Arguments{}
+	ags.AddArg("files", "desc", false, true).WithArgCount(2, 3)
+	err = ags.ParseArgs([]string{"a"})
+	assert.True(t, errors.As(err, &pe))
+	assert.Eq(t, gcli.ArgErrMissing, pe.Kind)
+	assert.Eq(t, "files", pe.ArgName)
+
+	err = ags.ParseArgs([]string{"a", "b", "c", "d"})
+	assert.True(t, errors.As(err, &pe))
+	assert.Eq(t, gcli.ArgErrTooMany, pe.Kind)
+	assert.Eq(t, "argument 'files' accepts at most 3 values, got 4", err.Error())
+}
+
+func TestArgument_WithAlias(t *testing.T) {
+	ags := gcli.Arguments{}
+	ags.AddArgument(gcli.NewArgument("path", "desc").WithAlias("file"))
+	ags.AddArg("mode", "desc").WithAlias("m", "type")
+
+	assert.NoErr(t, ags.ParseArgs([]string{"a.txt", "fast"}))
+	assert.Eq(t, "a.txt", ags.Arg("file").String())
+	assert.Same(t, ags.Arg("path"), ags.Arg("file"))
+	assert.True(t, ags.HasArg("type"))
+	assert.Eq(t, "fast", ags.Arg("m").String())
+	assert.Eq(t, []string{"m", "type"}, ags.Arg("mode").Aliases())
+	assert.Len(t, ags.Args(), 2)
+
+	assert.PanicsMsg(t, func() {
+		ags.AddArg("level", "desc").WithAlias("path")
+	}, "GCli: the argument alias 'path' already exists in command ''")
+	assert.PanicsMsg(t, func() {
+		ags.AddArg("file", "desc")
+	}, "GCli: the argument name 'file' already exists in command ''")
+}
+
+func TestArgument_AddHandler(t *testing.T) {
+	ags := gcli.Arguments{}
+	arg := ags.AddArg("name", "desc").WithValidator(func(val any) (any, error) {
+		if strings.TrimSpace(val.(string)) == "" {
+			return nil, errors.New("name is empty")
+		}
+		return val, nil
+	})
+	arg.Handler = func(val any) any {
+		return strings.TrimSpace(val.(string))
+	}
+	arg.AddHandler(func(val any) any {
+		return strings.ToLower(val.(string))
+	}).AddHandler(func(val any) any {
+		return "user:" + val.(string)
+	})
+
+	assert.NoErr(t, ags.ParseArgs([]string{"  InHere "}))
+	assert.Eq(t, "user:inhere", arg.Val())
+	assert.ErrMsg(t, ags.ParseArgs([]string{"  "}), "name is empty")
+}
+
+func TestArgument_AddValidator(t *testing.T) {
+	notEmpty := func(val any) (any, error) {
+		if val.(string) == "" {
+			return nil, errors.New("must not be empty")
+		}
+		return val, nil
+	}
+	hasExt := func(val any) (any, error) {
+		if filepath.Ext(val.(string)) == "" {
+			return nil, errors.New("must have an extension")
+		}
+		return val, nil
+	}
+
+	ags := gcli.Arguments{}
+	arg := ags.AddArg("file", "desc").WithValidator(func(val any) (any, error) {
+		return strings.TrimSpace(val.(string)), nil
+	}).AddValidator(notEmpty).AddValidator(hasExt).AddValidator(func(val any) (any, error) {
+		if strings.HasPrefix(val.(string), "/") {
+			return nil, errors.New("must be relative")
+		}
+		return val, nil
+	})
+
+	assert.NoErr(t, ags.ParseArgs([]string{" a.txt "}))
+	assert.Eq(t, "a.txt", arg.String())
+	assert.ErrMsg(t, ags.ParseArgs([]string{"/tmp/abc"}), "must have an extension")
+
+	arg.SetCollectErrors(true)
+	err := ags.ParseArgs([]string{"/tmp/abc"})
+	var errs gcli.ArgErrors
+	assert.True(t, errors.As(err, &errs))
+	assert.Eq(t, "must have an extension\nmust be relative", err.Error())
+}
+
+func TestArguments_LookupArg(t *testing.T) {
+	ags := gcli.Arguments{}
+	ags.AddArg("source", "desc")
+	ags.AddArg("Target", "desc").WithAlias("dst")
+	ags.AddArg("status", "desc")
+	ags.AddArg("sort", "desc").SetPositionalOnly()
+
+	cases := map[string]string{
+		"source": "source",
+		"dst":    "Target",
+		"target": "Target",
+		"SOURCE": "source",
+		"sou":    "source",
+		"stat":   "status",
+		"Tar":    "Target",
+	}
+	for name, want := range cases {
+		arg, ok := ags.LookupArg(name)
+		assert.True(t, ok, name)
+		assert.Eq(t, want, arg.Name)
+	}
+
+	// ambiguous or not exists
+	for _, name := range []string{"s", "", "sort", "none"} {
+		_, ok := ags.LookupArg(name)
+		assert.False(t, ok, name)
+	}
+}
+
+func TestArguments_SetNameValidator(t *testing.T) {
+	ags := gcli.Arguments{}
+	assert.PanicsMsg(t, func() {
+		ags.AddArg("db.host", "desc")
+	}, "GCli: the argument name 'db.host' is invalid, must match: ^[a-zA-Z][\\w-]*$")
+
+	ags = gcli.Arguments{}
+	ags.SetNameValidator(regexp.MustCompile(`^[a-z][\w.:-]*$`).MatchString)
+	ags.AddArg("db.host", "desc").WithAlias("db:host")
+	assert.NoErr(t, ags.ParseArgs([]string{"localhost"}))
+	assert.Eq(t, "localhost", ags.Arg("db:host").String())
+
+	assert.PanicsMsg(t, func() {
+		ags.AddArg("DB", "desc")
+	}, "GCli: the argument name 'DB' is invalid, it is rejected by the custom name validator")
+}
+
+func TestArguments_Reset(t *testing.T) {
+	ags := gcli.Arguments{}
+	ags.AddArg("src", "desc", true)
+	ags.AddArg("dst", "desc")
+	ags.AddArg("level", "desc").WithDefault("info")
+
+	_, err := ags.ParseArgsRemain([]string{"a", "b", "debug", "extra"})
+	assert.NoErr(t, err)
+	assert.Eq(t, []string{"extra"}, ags.Remaining())
+
+	ags.Reset()
+	assert.Empty(t, ags.Remaining())
+	for _, arg := range ags.Args() {
+		assert.False(t, arg.HasValue())
+		assert.Eq(t, gcli.ArgSourceNone, arg.ValueSource())
+	}
+
+	assert.NoErr(t, ags.ParseArgs([]string{"c"}))
+	assert.Eq(t, "c", ags.Arg("src").String())
+	assert.False(t, ags.Arg("dst").HasValue())
+	assert.Eq(t, "info", ags.Arg("level").String())
+}
+
+func TestArguments_Values_SetValues(t *testing.T) {
+	ags := gcli.Arguments{}
+	ags.AddArg("src", "desc", true)
+	ags.AddArg("num", "desc").WithValidator(func(val any) (any, error) {
+		return mathutil.ToInt(val)
+	})
+	ags.AddArg("files", "desc", false, true)
+
+	assert.NoErr(t, ags.ParseArgs([]string{"a", "12", "f1", "f2"}))
+	snap := ags.Values()
+	assert.Eq(t, map[string]any{"src": "a", "num": 12, "files": []string{"f1", "f2"}}, snap)
+
+	// replay from json
+	bs, err := json.Marshal(snap)
+	assert.NoErr(t, err)
+	var mp map[string]any
+	assert.NoErr(t, json.Unmarshal(bs, &mp))
+
+	ags.Reset()
+	assert.NoErr(t, ags.SetValues(mp))
+	assert.Eq(t, snap, ags.Values())
+	assert.Eq(t, gcli.ArgSourceSet, ags.Arg("src").ValueSource())
+
+	assert.NoErr(t, ags.SetValues(map[string]any{"files": "f3"}))
+	assert.Eq(t, []string{"f3"}, ags.Arg("files").Strings())
+
+	ags.Reset()
+	assert.ErrMsg(t, ags.SetValues(map[string]any{"other": 1}), "unknown argument name 'other'")
+	assert.ErrMsg(t, ags.SetValues(map[string]any{"src": []string{"a", "b"}}), "argument 'src' is not arrayed, cannot set multi values")
+	assert.ErrMsg(t, ags.SetValues(map[string]any{"num": "abc"}), `strconv.Atoi: parsing "abc": invalid syntax`)
+	assert.ErrMsg(t, ags.SetValues(map[string]any{"num": 2}), "must set value for the argument: src(position#0)")
+}
+
+func TestArguments_SetValues_decoded(t *testing.T) {
+	ags := gcli.Arguments{}
+	ags.AddArg("payload", "desc", true).WithDecode(gcli.DecodeBase64)
+
+	assert.NoErr(t, ags.ParseArgs([]string{"aGVsbG8="}))
+	vals := ags.Values()
+	assert.Eq(t, "hello", ags.Arg("payload").String())
+
+	// the decoded value is restored as is, not decode again
+	ags.Reset()
+	assert.NoErr(t, ags.SetValues(vals))
+	assert.Eq(t, "hello", ags.Arg("payload").String())
+	assert.Eq(t, vals, ags.Values())
+}
+
+func TestArguments_SetGroupValidator(t *testing.T) {
+	ags := gcli.Arguments{}
+	ags.AddArg("start", "desc", true)
+	ags.AddArg("end", "desc", true)
+
+	var called int
+	errRange := errors.New("the <start> must be less than <end>")
+	ags.SetGroupValidator(func(ags *gcli.Arguments) error {
+		called++
+		if ags.Arg("start").Int() >= ags.Arg("end").Int() {
+			return errRange
+		}
+		return nil
+	})
+
+	assert.NoErr(t, ags.ParseArgs([]string{"1", "5"}))
+	assert.Eq(t, errRange, ags.ParseArgs([]string{"5", "1"}))
+	assert.Eq(t, 2, called)
+
+	// not called on parse failed
+	assert.Err(t, ags.ParseArgs([]string{"1"}))
+	assert.Eq(t, 2, called)
+}
+
+func TestArgument_WithRaw(t *testing.T) {
+	ags := gcli.Arguments{}
+	ags.AddArg("script", "desc", true)
+	args := ags.AddArg("args", "desc", false, true).WithRaw().WithValidator(func(val any) (any, error) {
+		return nil, errors.New("should not be called")
+	})
+	args.Handler = func(val any) any { return "handled" }
+
+	assert.NoErr(t, ags.ParseArgs([]string{"run.sh", "-v", "--", "--name=abc"}))
+	assert.Eq(t, []string{"-v", "--", "--name=abc"}, args.Strings())
+	assert.Eq(t, []string{"-v", "--", "--name=abc"}, args.GetValue())
+
+	// with separator
+	ags = gcli.Arguments{}
+	ags.SetArgSeparator("--")
+	ags.AddArg("files", "desc", true, true)
+	rest := ags.AddArg("rest", "desc", false, true).WithRaw()
+	assert.NoErr(t, ags.ParseArgs([]string{"a", "--", "-x", "--", "y"}))
+	assert.Eq(t, []string{"a"}, ags.Arg("files").Strings())
+	assert.Eq(t, []string{"-x", "--", "y"}, rest.Strings())
+
+	assert.Panics(t, func() {
+		gcli.NewArgument("one", "desc").WithRaw()
+	})
+}
+
+func TestArguments_CheckArgs(t *testing.T) {
+	ags := gcli.Arguments{}
+	ags.AddArg("src", "desc", true)
+	ags.AddArg("dst", "desc")
+	ags.AddArg("mode", "desc")
+	assert.NoErr(t, ags.CheckArgs())
+
+	// changed after added
+	ags.Arg("dst").Arrayed = true
+	ags.Arg("mode").Required = true
+	ags.Arg("mode").Name = "src"
+
+	err := ags.CheckArgs()
+	var errs gcli.ArgErrors
+	assert.True(t, errors.As(err, &errs))
+	assert.Eq(t, `the argument name 'src' is duplicated
+the arrayed argument 'dst' must be the last one, but followed by 'src'
+required argument 'src' cannot be defined after optional argument 'dst'`, err.Error())
+
+	// per group on the separator is set
+	ags = gcli.Arguments{}
+	ags.SetArgSeparator("--")
+	ags.AddArg("files", "desc", false, true)
+	ags.AddArg("mode", "desc", true)
+	assert.NoErr(t, ags.CheckArgs())
+
+	// required is set after the default
+	ags = gcli.Arguments{}
+	ags.AddArg("level", "desc").WithDefault("info").Required = true
+	assert.ErrMsg(t, ags.CheckArgs(), "the required argument 'level' cannot have a default value")
+	assert.PanicsMsg(t, func() {
+		gcli.NewArgument("level", "desc", true).WithDefault("info")
+	}, "GCli: the required argument 'level' cannot have a default value")
+}
+
+func TestArguments_SetBindByName(t *testing.T) {
+	ags := gcli.Arguments{}
+	ags.SetBindByName(true)
+	ags.AddArg("verbose", "desc").WithDefault("0")
+	ags.AddArg("tags", "desc", false, true)
+	ags.AddArg("target", "desc", true)
+
+	assert.NoErr(t, ags.ParseArgs([]string{"target=prod", "tags=a", "tags=b"}))
+	assert.Eq(t, "prod", ags.Arg("target").String())
+	assert.Eq(t, "0", ags.Arg("verbose").String())
+	assert.Eq(t, []string{"a", "b"}, ags.Arg("tags").Strings())
+	assert.NoErr(t, ags.CheckArgs())
+
+	assert.ErrMsg(t, ags.ParseArgs([]string{"verbose=1"}), "must set value for the argument: target(position#2)")
+	assert.ErrMsg(t, ags.ParseArgs([]string{"prod"}), `the token "prod" must be in the form 'name=value' on bind by name`)
+	assert.ErrMsg(t, ags.ParseArgs([]string{"env=prod"}), `unknown argument name 'env' in the token "env=prod", valid names: verbose, tags, target`)
+}
+
+func TestArguments_ParseNamedArgs(t *testing.T) {
+	ags := gcli.Arguments{}
+	ags.AddArg("src", "desc", true)
+	ags.AddArg("dst", "desc", true)
+	ags.AddArg("mode", "desc").WithDefault("slow").WithChoices([]string{"fast", "slow"})
+
+	assert.NoErr(t, ags.ParseNamedArgs([]string{"mode=fast", "dst=./b", "./a"}))
+	assert.Eq(t, "./a", ags.Arg("src").String())
+	assert.Eq(t, "./b", ags.Arg("dst").String())
+	assert.Eq(t, "fast", ags.Arg("mode").String())
+
+	assert.NoErr(t, ags.ParseNamedArgs([]string{"./a", "./b"}))
+	assert.Eq(t, "slow", ags.Arg("mode").String())
+
+	assert.ErrMsg(t, ags.ParseNamedArgs([]string{"./a", "./b", "mode=none"}), "argument 'mode' must be one of: fast, slow")
+	assert.ErrMsg(t, ags.ParseNamedArgs([]string{"src=./a"}), "must set value for the argument: dst(position#1)")
+	assert.ErrMsg(t, ags.ParseNamedArgs([]string{"from=./a"}), `unknown argument name 'from' in the token "from=./a", valid names: src, dst, mode`)
+}
+
+func TestArgument_Duration_Bytes(t *testing.T) {
+	ags := gcli.Arguments{}
+	timeout := ags.AddArg("timeout", "desc")
+	size := ags.AddArg("size", "desc")
+
+	d, err := timeout.Duration()
+	assert.NoErr(t, err)
+	assert.Eq(t, time.Duration(0), d)
+
+	assert.NoErr(t, ags.ParseArgs([]string{"1m30s", "10MB"}))
+	d, err = timeout.Duration()
+	assert.NoErr(t, err)
+	assert.Eq(t, 90*time.Second, d)
+
+	tests := map[string]int64{
+		"10MB":   10_000_000,
+		"2GiB":   2 << 30,
+		"1.5k":   1536,
+		"512":    512,
+		"64 kib": 64 << 10,
+		"3b":     3,
+	}
+	for in, want := range tests {
+		assert.NoErr(t, size.SetValue(in))
+		n, err := size.Bytes()
+		assert.NoErr(t, err, in)
+		assert.Eq(t, want, n, in)
+	}
+
+	assert.NoErr(t, ags.ParseArgs([]string{"30", "10XB"}))
+	_, err = timeout.Duration()
+	assert.ErrMsg(t, err, `argument 'timeout' expects a duration(eg: 30s, 1h5m), got "30"`)
+	_, err = size.Bytes()
+	assert.ErrMsg(t, err, `argument 'size' expects a byte size(eg: 10MB, 2GiB), got "10XB"`)
+
+	// with handler
+	timeout.Handler = func(val any) any { return val.(string) + "s" }
+	d, err = timeout.Duration()
+	assert.NoErr(t, err)
+	assert.Eq(t, 30*time.Second, d)
+}
+
+func TestArgument_Ints_Floats(t *testing.T) {
+	ags := gcli.Arguments{}
+	one := ags.AddArg("one", "desc")
+	ports := ags.AddArg("ports", "desc", false, true)
+
+	ints, err := ports.Ints()
+	assert.NoErr(t, err)
+	assert.Eq(t, []int{}, ints)
+
+	assert.NoErr(t, ags.ParseArgs([]string{"a", "80", "443", "8080"}))
+	ints, err = ports.Ints()
+	assert.NoErr(t, err)
+	assert.Eq(t, []int{80, 443, 8080}, ints)
+
+	floats, err := ports.Floats()
+	assert.NoErr(t, err)
+	assert.Eq(t, []float64{80, 443, 8080}, floats)
+
+	assert.NoErr(t, ags.ParseArgs([]string{"a", "80", "1.5", "x"}))
+	_, err = ports.Ints()
+	assert.ErrMsg(t, err, `argument 'ports' element #1 "1.5" is not an integer`)
+	_, err = ports.Floats()
+	assert.ErrMsg(t, err, `argument 'ports' element #2 "x" is not a float`)
+
+	_, err = one.Ints()
+	assert.ErrMsg(t, err, "argument 'one' is not arrayed")
+}
+
+func TestArgument_Source(t *testing.T) {
+	t.Setenv("GCLI_TEST_SRC_ENV", "e")
+
+	ags := gcli.Arguments{}
+	in := ags.AddArg("in", "desc")
+	env := ags.AddArg("env", "desc").WithEnv("GCLI_TEST_SRC_ENV").WithDefault("x")
+	def := ags.AddArg("def", "desc").WithDefault("d")
+	none := ags.AddArg("none", "desc")
+
+	assert.NoErr(t, ags.ParseArgs([]string{"a"}))
+	assert.Eq(t, "input", in.Source())
+	assert.Eq(t, "env", env.Source())
+	assert.Eq(t, "default", def.Source())
+	assert.Eq(t, "", none.Source())
+
+	assert.NoErr(t, none.SetValue("v"))
+	assert.Eq(t, "set", none.Source())
+}
+
+func TestArgument_WithFileExpand(t *testing.T) {
+	dir := t.TempDir()
+	hosts := filepath.Join(dir, "hosts.txt")
+	assert.NoErr(t, os.WriteFile(hosts, []byte("# the hosts\na.com\n\n  b.com \n#c.com\n"), 0644))
+	body := filepath.Join(dir, "body.json")
+	assert.NoErr(t, os.WriteFile(body, []byte(`{"k": 1}`), 0644))
+
+	ags := gcli.Arguments{}
+	ags.AddArg("body", "desc").WithFileExpand()
+	ags.AddArg("hosts", "desc", false, true).WithFileExpand()
+
+	assert.NoErr(t, ags.ParseArgs([]string{"@" + body, "x.com", "@" + hosts, "@"}))
+	assert.Eq(t, `{"k": 1}`, ags.Arg("body").String())
+	assert.Eq(t, []string{"x.com", "a.com", "b.com", "@"}, ags.Arg("hosts").Strings())
+
+	err := ags.ParseArgs([]string{"@" + filepath.Join(dir, "none.txt")})
+	assert.Err(t, err)
+	assert.Contains(t, err.Error(), "argument 'body' cannot read the file")
+}
+
+func TestArgument_WithUnique_WithSorted(t *testing.T) {
+	ags := gcli.Arguments{}
+	tags := ags.AddArg("tags", "desc", false, true).WithUnique().AddHandler(func(val any) any {
+		ss := val.([]string)
+		for i, s := range ss {
+			ss[i] = strings.ToLower(s)
+		}
+		return ss
+	})
+
+	assert.NoErr(t, ags.ParseArgs([]string{"b", "A", "b", "a", "c"}))
+	assert.Eq(t, []string{"b", "a", "c"}, tags.Strings())
+
+	tags.WithSorted()
+	assert.NoErr(t, ags.ParseArgs([]string{"b", "A", "b", "a", "c"}))
+	assert.Eq(t, []string{"a", "b", "c"}, tags.Strings())
+
+	// numbers
+	ags = gcli.Arguments{}
+	nums := ags.AddArg("nums", "desc", false, true).WithSorted().WithValidator(func(val any) (any, error) {
+		return arrutil.StringsToInts(val.([]string))
+	})
+	assert.NoErr(t, ags.ParseArgs([]string{"10", "9", "100", "9"}))
+	assert.Eq(t, []int{9, 9, 10, 100}, nums.Val())
+
+	assert.Panics(t, func() {
+		gcli.NewArgument("one", "desc").WithUnique()
+	})
+	assert.Panics(t, func() {
+		gcli.NewArgument("one", "desc").WithSorted()
+	})
+}
+
+func TestArguments_SetHelpFormatter(t *testing.T) {
+	ags := gcli.Arguments{}
+	ags.AddArg("action", "desc", true).WithChoices([]string{"start", "stop"})
+	ags.AddArg("files", "desc", false, true)
+
+	assert.Eq(t, "files...", ags.ArgHelpName(ags.Arg("files")))
+
+	ags.SetHelpFormatter(func(a *gcli.Argument) string {
+		if len(a.Choices()) > 0 {
+			return "<" + strings.Join(a.Choices(), "|") + ">"
+		}
+		if a.Arrayed && !a.Required {
+			return "[" + a.Name + "...]"
+		}
+		return a.HelpName()
+	})
+
+	assert.Eq(t, "<start|stop>", ags.ArgHelpName(ags.Arg("action")))
+	assert.Eq(t, "[files...]", ags.ArgHelpName(ags.Arg("files")))
+	assert.Contains(t, ags.MarkdownTable(), "| [files...] | no | yes |")
+}
+
+func TestArguments_MustParseArgs(t *testing.T) {
+	ags := gcli.Arguments{}
+	ags.AddArg("name", "desc", true)
+
+	ags.MustParseArgs([]string{"inhere"})
+	assert.Eq(t, "inhere", ags.Arg("name").String())
+
+	assert.PanicsMsg(t, func() {
+		ags.MustParseArgs(nil)
+	}, "GCli: parse arguments error: must set value for the argument: name(position#0)")
+}
+
+func TestArguments_BindArgs(t *testing.T) {
+	ags := gcli.Arguments{}
+	ags.SetValidateNum(true)
+	ags.AddArg("name", "desc", true)
+	ags.AddArg("num", "desc").WithValidator(func(val any) (any, error) {
+		if _, ok := val.(int); !ok {
+			return nil, errors.New("num must be an int")
+		}
+		return val, nil
+	})
+	ags.AddArg("level", "desc").WithDefault("info")
+
+	assert.NoErr(t, ags.BindArgs([]any{"deploy", 3}))
+	assert.Eq(t, "deploy", ags.Arg("name").Val())
+	assert.Eq(t, 3, ags.Arg("num").Val())
+	assert.Eq(t, "info", ags.Arg("level").Val())
+
+	assert.ErrMsg(t, ags.BindArgs([]any{"deploy", "3"}), "num must be an int")
+	assert.ErrMsg(t, ags.BindArgs(nil), "must set value for the argument: name(position#0)")
+	assert.ErrMsg(t, ags.BindArgs([]any{"a", 1, "debug", true}), "entered too many arguments: [true]")
+
+	// collect all errors
+	ags.SetValidateMode(gcli.ValidateAll)
+	err := ags.BindArgs([]any{"a", "1", "debug", true})
+	assert.ErrMsg(t, err, "num must be an int\nentered too many arguments: [true]")
+	assert.Len(t, err.(gcli.ArgErrors), 2)
+	ags.SetValidateMode(gcli.ValidateFailFast)
+
+	// arrayed
+	ags = gcli.Arguments{}
+	ags.AddArg("name", "desc", true)
+	ags.AddArg("flags", "desc", false, true).WithArgCount(1, 2)
+	assert.NoErr(t, ags.BindArgs([]any{"a", true, 1.5}))
+	assert.Eq(t, []any{true, 1.5}, ags.Arg("flags").Val())
+	assert.ErrMsg(t, ags.BindArgs([]any{"a", true, 1.5, 3}), "argument 'flags' accepts at most 2 values, got 3")
+}
+
+func TestArguments_Signature(t *testing.T) {
+	ags := gcli.Arguments{}
+	assert.Eq(t, "", ags.Signature())
+
+	ags.AddArg("name", "desc", true)
+	ags.AddArg("file", "desc", true)
+	ags.AddArg("tags", "desc", false, true)
+	assert.Eq(t, "<name> <file> [tags...]", ags.Signature())
+
+	ags = gcli.Arguments{}
+	ags.AddArg("files", "desc", true, true)
+	assert.Eq(t, "<files...>", ags.Signature())
+
+	// positional-only use the bare name inside the wrapper
+	ags = gcli.Arguments{}
+	ags.AddArg("first", "desc", true).SetPositionalOnly()
+	ags.AddArg("second", "desc").SetPositionalOnly()
+	ags.AddArg("rest", "desc", false, true).SetPositionalOnly()
+	assert.Eq(t, "<ARG0> [ARG1] [ARG2...]", ags.Signature())
+	assert.Eq(t, "<ARG1>", ags.ArgByIndex(1).HelpName())
+}
+
+func TestArgument_WithContextValidator(t *testing.T) {
+	ags := gcli.Arguments{}
+	ags.AddArg("start", "desc", true)
+	ags.AddArg("end", "desc", true).WithContextValidator(func(val any, ags *gcli.Arguments) (any, error) {
+		if mathutil.QuietInt(val) <= ags.Arg("start").Int() {
+			return nil, errors.New("the <end> must be greater than <start>")
+		}
+		return val, nil
+	})
+
+	assert.NoErr(t, ags.ParseArgs([]string{"1", "5"}))
+	assert.ErrMsg(t, ags.ParseArgs([]string{"5", "1"}), "the <end> must be greater than <start>")
+
+	// the clone and dry-run read the arguments being parsed
+	assert.NoErr(t, ags.ParseArgs([]string{"1", "5"}))
+	assert.True(t, ags.CanParse([]string{"1", "3"}))
+	assert.False(t, ags.CanParse([]string{"8", "6"}))
+
+	nags := ags.CloneWithValues()
+	assert.NoErr(t, nags.ParseArgs([]string{"1", "3"}))
+	assert.ErrMsg(t, nags.ParseArgs([]string{"7", "6"}), "the <end> must be greater than <start>")
+	assert.Eq(t, 1, ags.Arg("start").Int())
+}
+
+func TestArgument_WithType(t *testing.T) {
+	ags := gcli.Arguments{}
+	num := ags.AddArg("num", "desc").WithType("int")
+	ratios := ags.AddArg("ratios", "desc", false, true).WithType("float")
+
+	assert.Eq(t, "int", num.Type())
+	assert.NoErr(t, ags.ParseArgs([]string{"-5", "-3.14", "2"}))
+	assert.Eq(t, -5, num.Val())
+	assert.Eq(t, []float64{-3.14, 2}, ratios.Val())
+
+	assert.ErrMsg(t, ags.ParseArgs([]string{"x"}), `argument 'num' expects an integer, got "x"`)
+	assert.ErrMsg(t, ags.ParseArgs([]string{"1", "y"}), `argument 'ratios' expects a float, got "y"`)
+
+	assert.PanicsMsg(t, func() {
+		gcli.NewArgument("one", "desc").WithType("bytes")
+	}, "GCli: invalid type 'bytes' for argument 'one', allow: int, float, string")
+}
+
+func TestArguments_UnreadArgs(t *testing.T) {
+	ags := gcli.Arguments{}
+	ags.AddArg("src", "desc")
+	dst := ags.AddArg("dst", "desc")
+	ags.AddArg("mode", "desc")
+	ags.AddArg("level", "desc")
+	ags.AddArg("unused", "desc")
+
+	assert.NoErr(t, ags.ParseArgs([]string{"a", "b", "c"}))
+	assert.Eq(t, []string{"src", "dst", "mode", "level", "unused"}, ags.UnreadArgs())
+
+	_ = ags.Arg("src").String()
+	_ = dst.GetValue()
+	_ = ags.ArgByIndex(2)
+	_, _ = ags.LookupArg("lev")
+	assert.Eq(t, []string{"unused"}, ags.UnreadArgs())
+
+	// the arguments bound into struct are read
+	ags = gcli.Arguments{}
+	ags.AddArg("src", "desc")
+	ags.AddArg("dst", "desc")
+	ags.AddArg("mode", "desc")
+	assert.NoErr(t, ags.ParseArgs([]string{"a", "b"}))
+
+	var pos struct {
+		Src string `pos:"src"`
+	}
+	assert.NoErr(t, ags.BindToStruct(&pos, "pos"))
+	var fill struct {
+		Mode string // unbound still be read
+	}
+	assert.NoErr(t, ags.FillStruct(&fill))
+	assert.Eq(t, []string{"dst"}, ags.UnreadArgs())
+
+	ags = gcli.Arguments{}
+	var opts struct {
+		Host string
+		Port int
+	}
+	assert.NoErr(t, ags.BindStruct(&opts))
+	assert.NoErr(t, ags.ParseArgs([]string{"local", "80"}))
+	assert.Empty(t, ags.UnreadArgs())
+	assert.Eq(t, 80, opts.Port)
+}
@@ -854,6 +854,21 @@ func (fs *Flags) HasFlagMeta(name string) bool {
 // LookupFlag get flag.Flag by name
 func (fs *Flags) LookupFlag(name string) *flag.Flag { return fs.fSet.Lookup(name) }
 
+// check the option requires a value, short name is resolved. eg: "-n VAL"
+func (fs *Flags) needsValue(name string) bool {
+	if long, ok := fs.shorts[name]; ok {
+		name = long
+	}
+
+	f := fs.fSet.Lookup(name)
+	if f == nil {
+		return false
+	}
+
+	bf, ok := f.Value.(interface{ IsBoolFlag() bool })
+	return !ok || !bf.IsBoolFlag()
+}
+
 // FlagMeta get FlagMeta by name
 func (fs *Flags) FlagMeta(name string) *FlagMeta { return fs.metas[name] }
 
@@ -6,6 +6,7 @@ require (
 	github.com/gookit/color v1.5.1
 	github.com/gookit/goutil v0.5.9
 	golang.org/x/crypto v0.0.0-20210220033148-5ea612d1eb83
+	golang.org/x/text v0.3.7
 )
 
 require (
@@ -13,5 +14,4 @@ require (
 	github.com/xo/terminfo v0.0.0-20210125001918-ca9a967f8778 // indirect
 	golang.org/x/sys v0.0.0-20220811171246-fbc7d0a398ab // indirect
 	golang.org/x/term v0.0.0-20210220032956-6a3ed077a48d // indirect
-	golang.org/x/text v0.3.7 // indirect
 )
@@ -1,13 +1,23 @@
 package gcli
 
 import (
+	"bytes"
+	"compress/gzip"
 	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
 	"regexp"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/gookit/color"
 	"github.com/gookit/goutil/arrutil"
 	"github.com/gookit/goutil/common"
+	"github.com/gookit/goutil/errorx"
 	"github.com/gookit/goutil/stdutil"
 	"github.com/gookit/goutil/strutil"
 )
@@ -204,6 +214,8 @@ const (
 var (
 	// good name for option and argument
 	goodName = regexp.MustCompile(regGoodName)
+	// negative number. eg: -5, -3.14
+	negNumReg = regexp.MustCompile(`^-(\d+\.?\d*|\.\d+)$`)
 	// match a good command name
 	goodCmdId = regexp.MustCompile(regGoodCmdId)
 	// match a good command name
@@ -334,3 +346,219 @@ func wrapColor2string(s string) string {
 	}
 	return s
 }
+
+/*************************************************************
+ * value helpers
+ *************************************************************/
+
+// the multipliers of the byte size units
+var byteUnits = map[string]float64{
+	"":    1,
+	"b":   1,
+	"kb":  1e3,
+	"mb":  1e6,
+	"gb":  1e9,
+	"tb":  1e12,
+	"k":   1 << 10,
+	"m":   1 << 20,
+	"g":   1 << 30,
+	"t":   1 << 40,
+	"kib": 1 << 10,
+	"mib": 1 << 20,
+	"gib": 1 << 30,
+	"tib": 1 << 40,
+}
+
+// parse the byte size string. eg: 10MB, 2GiB, 1.5k
+func parseByteSize(str string) (int64, error) {
+	str = strings.TrimSpace(str)
+	end := strings.IndexFunc(str, func(r rune) bool {
+		return (r < '0' || r > '9') && r != '.'
+	})
+	if end < 0 {
+		end = len(str)
+	}
+
+	num, err := strconv.ParseFloat(str[:end], 64)
+	if err != nil || num < 0 {
+		return 0, errorx.Rawf("invalid byte size %q", str)
+	}
+
+	mul, ok := byteUnits[strings.ToLower(strings.TrimSpace(str[end:]))]
+	if !ok {
+		return 0, errorx.Rawf("invalid byte size unit in %q", str)
+	}
+	return int64(num * mul), nil
+}
+
+// append the new slice value to the prev slice value. returns newVal if cannot append.
+func appendValues(prev, newVal any) any {
+	if prev == nil {
+		return newVal
+	}
+
+	pv, nv := reflect.ValueOf(prev), reflect.ValueOf(newVal)
+	if pv.Kind() != reflect.Slice || pv.Type() != nv.Type() {
+		return newVal
+	}
+
+	ns := reflect.MakeSlice(pv.Type(), 0, pv.Len()+nv.Len())
+	return reflect.AppendSlice(reflect.AppendSlice(ns, pv), nv).Interface()
+}
+
+// decompress the gzip data
+func gunzip(bs []byte) ([]byte, error) {
+	zr, err := gzip.NewReader(bytes.NewReader(bs))
+	if err != nil {
+		return nil, err
+	}
+
+	defer zr.Close()
+	return io.ReadAll(zr)
+}
+
+// a simple value cache with TTL
+type valueCache struct {
+	sync.Mutex
+	ttl  time.Duration
+	data map[string]cacheItem
+}
+
+type cacheItem struct {
+	val    any
+	expire time.Time
+}
+
+func newValueCache(ttl time.Duration) *valueCache {
+	return &valueCache{ttl: ttl, data: make(map[string]cacheItem)}
+}
+
+func (c *valueCache) get(key string) (any, bool) {
+	c.Lock()
+	defer c.Unlock()
+
+	item, ok := c.data[key]
+	if !ok || time.Now().After(item.expire) {
+		return nil, false
+	}
+	return item.val, true
+}
+
+func (c *valueCache) set(key string, val any) {
+	if c.ttl <= 0 {
+		return
+	}
+
+	c.Lock()
+	c.data[key] = cacheItem{val: val, expire: time.Now().Add(c.ttl)}
+	c.Unlock()
+}
+
+// check the path is writable, or can be created in the parent dir.
+func checkWritable(path string) error {
+	fi, err := os.Stat(path)
+	if err == nil {
+		if fi.IsDir() {
+			return checkDirWritable(path)
+		}
+
+		f, err := os.OpenFile(path, os.O_WRONLY, 0)
+		if err != nil {
+			if os.IsPermission(err) {
+				return errorx.Raw("permission denied")
+			}
+			return err
+		}
+		return f.Close()
+	}
+
+	if !os.IsNotExist(err) {
+		return err
+	}
+
+	parent := filepath.Dir(path)
+	if fi, err = os.Stat(parent); err != nil || !fi.IsDir() {
+		return errorx.Rawf("parent directory %q is missing", parent)
+	}
+	return checkDirWritable(parent)
+}
+
+// check can create file in the dir
+func checkDirWritable(dir string) error {
+	f, err := os.CreateTemp(dir, ".gcli-check-*")
+	if err != nil {
+		if os.IsPermission(err) {
+			return errorx.Raw("permission denied")
+		}
+		return err
+	}
+
+	_ = f.Close()
+	return os.Remove(f.Name())
+}
+
+// deep copy the slice and map value, other values are returned directly.
+func deepCopyValue(val any) any {
+	if val == nil {
+		return nil
+	}
+	return deepCopyReflect(reflect.ValueOf(val)).Interface()
+}
+
+func deepCopyReflect(rv reflect.Value) reflect.Value {
+	switch rv.Kind() {
+	case reflect.Slice:
+		if rv.IsNil() {
+			return rv
+		}
+
+		ns := reflect.MakeSlice(rv.Type(), rv.Len(), rv.Len())
+		for i := 0; i < rv.Len(); i++ {
+			ns.Index(i).Set(deepCopyReflect(rv.Index(i)))
+		}
+		return ns
+	case reflect.Map:
+		if rv.IsNil() {
+			return rv
+		}
+
+		nm := reflect.MakeMapWithSize(rv.Type(), rv.Len())
+		iter := rv.MapRange()
+		for iter.Next() {
+			nm.SetMapIndex(iter.Key(), deepCopyReflect(iter.Value()))
+		}
+		return nm
+	case reflect.Interface:
+		if rv.IsNil() {
+			return rv
+		}
+
+		nv := reflect.New(rv.Type()).Elem()
+		nv.Set(deepCopyReflect(rv.Elem()))
+		return nv
+	}
+	return rv
+}
+
+// load the rewrite table from file. each line is "OLD=NEW"
+func loadRewriteTable(path string) (map[string]string, error) {
+	bs, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	table := make(map[string]string)
+	for i, line := range strings.Split(string(bs), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || line[0] == '#' {
+			continue
+		}
+
+		key, val, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, errorx.Rawf("invalid line #%d %q, must match `OLD=NEW`", i+1, line)
+		}
+		table[strings.TrimSpace(key)] = strings.TrimSpace(val)
+	}
+	return table, nil
+}